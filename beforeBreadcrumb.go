@@ -0,0 +1,139 @@
+package sentry
+
+import (
+	"regexp"
+	"time"
+)
+
+// BreadcrumbInfo describes a breadcrumb which is about to be included in
+// an event. It is provided to BeforeBreadcrumb hooks so that they can
+// inspect, modify or drop the breadcrumb.
+type BreadcrumbInfo struct {
+	Timestamp time.Time
+	Type      string
+	Message   string
+	Category  string
+	Level     Severity
+	Data      map[string]interface{}
+}
+
+// FilterBreadcrumbCategories creates a BeforeBreadcrumb hook which will drop
+// any breadcrumbs with one of the provided categories.
+func FilterBreadcrumbCategories(categories ...string) func(crumb *BreadcrumbInfo) *BreadcrumbInfo {
+	filtered := make(map[string]struct{}, len(categories))
+	for _, category := range categories {
+		filtered[category] = struct{}{}
+	}
+
+	return func(crumb *BreadcrumbInfo) *BreadcrumbInfo {
+		if _, ok := filtered[crumb.Category]; ok {
+			return nil
+		}
+
+		return crumb
+	}
+}
+
+// FilterBreadcrumbLevel creates a BeforeBreadcrumb hook which will drop any
+// breadcrumbs which are less severe than the provided level. Breadcrumbs
+// without a level are treated as having the Info level, which is what
+// Sentry will display them as.
+func FilterBreadcrumbLevel(minimum Severity) func(crumb *BreadcrumbInfo) *BreadcrumbInfo {
+	return func(crumb *BreadcrumbInfo) *BreadcrumbInfo {
+		level := crumb.Level
+		if level == "" {
+			level = Info
+		}
+
+		current, ok := severityOrder[level]
+		if !ok {
+			return crumb
+		}
+
+		if current < severityOrder[minimum] {
+			return nil
+		}
+
+		return crumb
+	}
+}
+
+// FilterBreadcrumbURLs creates a BeforeBreadcrumb hook which will drop any
+// breadcrumbs whose "url" data matches one of the provided patterns, like
+// those recorded for requests to your health check endpoints.
+func FilterBreadcrumbURLs(patterns ...*regexp.Regexp) func(crumb *BreadcrumbInfo) *BreadcrumbInfo {
+	return func(crumb *BreadcrumbInfo) *BreadcrumbInfo {
+		url, ok := crumb.Data["url"].(string)
+		if !ok {
+			return crumb
+		}
+
+		for _, pattern := range patterns {
+			if pattern.MatchString(url) {
+				return nil
+			}
+		}
+
+		return crumb
+	}
+}
+
+// severityOrder is used to compare the severity of breadcrumbs.
+var severityOrder = map[Severity]int{
+	Debug:   0,
+	Info:    1,
+	Warning: 2,
+	Error:   3,
+	Fatal:   4,
+}
+
+func (l *breadcrumbsList) WithBeforeBreadcrumb(hook func(crumb *BreadcrumbInfo) *BreadcrumbInfo) BreadcrumbsList {
+	if hook == nil {
+		return l
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.beforeBreadcrumb = append(l.beforeBreadcrumb, hook)
+	return l
+}
+
+// applyBeforeBreadcrumb runs a list's BeforeBreadcrumb hooks against a
+// copy of a breadcrumb, returning nil if it should be dropped.
+func applyBeforeBreadcrumb(hooks []func(crumb *BreadcrumbInfo) *BreadcrumbInfo, b *breadcrumb) *breadcrumb {
+	if len(hooks) == 0 {
+		return b
+	}
+
+	info := b.info()
+	for _, hook := range hooks {
+		if info = hook(info); info == nil {
+			return nil
+		}
+	}
+
+	return newBreadcrumbFromInfo(info)
+}
+
+// info describes this breadcrumb using a BreadcrumbInfo.
+func (b *breadcrumb) info() *BreadcrumbInfo {
+	return &BreadcrumbInfo{
+		Timestamp: b.Timestamp,
+		Type:      b.Type,
+		Message:   b.Message,
+		Category:  b.Category,
+		Level:     b.Level,
+		Data:      b.Data,
+	}
+}
+
+// newBreadcrumbFromInfo creates a breadcrumb from its description.
+func newBreadcrumbFromInfo(info *BreadcrumbInfo) *breadcrumb {
+	b := newBreadcrumb(info.Type, info.Data)
+	b.WithTimestamp(info.Timestamp)
+	b.Message = info.Message
+	b.Category = info.Category
+	b.Level = info.Level
+	return b
+}
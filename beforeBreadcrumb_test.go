@@ -0,0 +1,166 @@
+package sentry
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleBreadcrumbsList_WithBeforeBreadcrumb() {
+	DefaultBreadcrumbs().
+		// Don't include requests to our health check endpoint
+		WithBeforeBreadcrumb(FilterBreadcrumbURLs(regexp.MustCompile(`/healthz$`))).
+		// Or any debug breadcrumbs
+		WithBeforeBreadcrumb(FilterBreadcrumbLevel(Info)).
+		// And remove any email addresses from the messages we do include
+		WithBeforeBreadcrumb(func(crumb *BreadcrumbInfo) *BreadcrumbInfo {
+			crumb.Message = regexp.MustCompile(`\S+@\S+`).ReplaceAllString(crumb.Message, "[email]")
+			return crumb
+		})
+
+	// Breadcrumbs are filtered when an event is captured, so this one will
+	// still be dropped even though its level was set after it was created
+	DefaultBreadcrumbs().NewDefault(map[string]interface{}{"cache": "miss"}).WithLevel(Debug)
+
+	NewClient().Capture(Message("Breadcrumbs are filtered before being sent"))
+}
+
+func TestBeforeBreadcrumb(t *testing.T) {
+	snapshotOf := func(l BreadcrumbsList) []Breadcrumb {
+		return l.(*breadcrumbsList).snapshot().breadcrumbs
+	}
+
+	t.Run("WithBeforeBreadcrumb()", func(t *testing.T) {
+		l := NewBreadcrumbsList(10)
+		assert.Equal(t, l, l.WithBeforeBreadcrumb(nil), "it should return the list for chaining")
+		assert.Empty(t, l.(*breadcrumbsList).beforeBreadcrumb, "it should ignore nil hooks")
+
+		l.NewDefault(map[string]interface{}{"keep": true}).WithMessage("first")
+		l.NewDefault(nil).WithMessage("second")
+
+		calls := []string{}
+		assert.Equal(t, l, l.WithBeforeBreadcrumb(func(crumb *BreadcrumbInfo) *BreadcrumbInfo {
+			calls = append(calls, "first:"+crumb.Message)
+			if crumb.Message == "second" {
+				return nil
+			}
+
+			crumb.Message = "modified"
+			crumb.Data["added"] = true
+			return crumb
+		}), "it should return the list for chaining")
+
+		l.WithBeforeBreadcrumb(func(crumb *BreadcrumbInfo) *BreadcrumbInfo {
+			calls = append(calls, "second:"+crumb.Message)
+			return crumb
+		})
+
+		crumbs := snapshotOf(l)
+		assert.Equal(t, []string{"first:first", "second:modified", "first:second"}, calls, "the hooks should be called in order, stopping when a breadcrumb is dropped")
+		require.Len(t, crumbs, 1, "the dropped breadcrumb should not be included")
+
+		b, ok := crumbs[0].(*breadcrumb)
+		require.True(t, ok, "it should actually be a *breadcrumb object")
+		assert.Equal(t, "modified", b.Message, "the breadcrumb should be modified")
+		assert.Equal(t, map[string]interface{}{"keep": true, "added": true}, b.Data, "the breadcrumb's data should be modified")
+
+		original := l.(*breadcrumbsList).list()
+		require.Len(t, original, 2, "the list should still contain every breadcrumb")
+		assert.Equal(t, "first", original[0].(*breadcrumb).Message, "the list's breadcrumbs should not be modified")
+		assert.Equal(t, map[string]interface{}{"keep": true}, original[0].(*breadcrumb).Data, "the list's breadcrumb data should not be modified")
+	})
+
+	t.Run("Chained WithLevel()", func(t *testing.T) {
+		l := NewBreadcrumbsList(10)
+
+		calls := 0
+		l.WithBeforeBreadcrumb(func(crumb *BreadcrumbInfo) *BreadcrumbInfo {
+			calls++
+			return crumb
+		})
+		l.WithBeforeBreadcrumb(FilterBreadcrumbLevel(Warning))
+
+		l.NewDefault(nil).WithMessage("db down").WithLevel(Error)
+		l.NewDefault(nil).WithMessage("cache miss").WithLevel(Debug)
+		assert.Equal(t, 0, calls, "the hooks should not be called when breadcrumbs are added")
+
+		crumbs := snapshotOf(l)
+		assert.Equal(t, 2, calls, "the hooks should be called once for each breadcrumb")
+		require.Len(t, crumbs, 1, "it should use the level set after the breadcrumb was created")
+		assert.Equal(t, "db down", crumbs[0].(*breadcrumb).Message, "it should keep breadcrumbs whose level was raised")
+	})
+
+	t.Run("info()", func(t *testing.T) {
+		ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		b := newBreadcrumb("http", map[string]interface{}{"url": "/"})
+		b.WithMessage("message").WithCategory("category").WithLevel(Warning).WithTimestamp(ts)
+
+		info := b.info()
+		assert.Equal(t, &BreadcrumbInfo{
+			Timestamp: ts,
+			Type:      "http",
+			Message:   "message",
+			Category:  "category",
+			Level:     Warning,
+			Data:      map[string]interface{}{"url": "/"},
+		}, info, "it should describe the breadcrumb")
+
+		assert.Equal(t, b, newBreadcrumbFromInfo(info), "it should be possible to recreate the breadcrumb")
+	})
+
+	t.Run("FilterBreadcrumbCategories()", func(t *testing.T) {
+		filter := FilterBreadcrumbCategories("health", "noise")
+
+		assert.Nil(t, filter(&BreadcrumbInfo{Category: "health"}), "it should drop breadcrumbs in a filtered category")
+		assert.Nil(t, filter(&BreadcrumbInfo{Category: "noise"}), "it should drop breadcrumbs in every filtered category")
+		assert.NotNil(t, filter(&BreadcrumbInfo{Category: "query"}), "it should keep breadcrumbs in other categories")
+		assert.NotNil(t, filter(&BreadcrumbInfo{}), "it should keep breadcrumbs without a category")
+	})
+
+	t.Run("FilterBreadcrumbLevel()", func(t *testing.T) {
+		filter := FilterBreadcrumbLevel(Warning)
+
+		assert.Nil(t, filter(&BreadcrumbInfo{Level: Debug}), "it should drop less severe breadcrumbs")
+		assert.Nil(t, filter(&BreadcrumbInfo{Level: Info}), "it should drop less severe breadcrumbs")
+		assert.Nil(t, filter(&BreadcrumbInfo{}), "it should treat breadcrumbs without a level as info")
+		assert.NotNil(t, filter(&BreadcrumbInfo{Level: Warning}), "it should keep breadcrumbs with the minimum level")
+		assert.NotNil(t, filter(&BreadcrumbInfo{Level: Fatal}), "it should keep more severe breadcrumbs")
+		assert.NotNil(t, filter(&BreadcrumbInfo{Level: Severity("custom")}), "it should keep breadcrumbs with unknown levels")
+
+		assert.Nil(t, FilterBreadcrumbLevel(Info)(&BreadcrumbInfo{Level: Debug}), "it should drop debug breadcrumbs when the minimum is info")
+		assert.NotNil(t, FilterBreadcrumbLevel(Info)(&BreadcrumbInfo{}), "it should keep breadcrumbs without a level when the minimum is info")
+	})
+
+	t.Run("FilterBreadcrumbURLs()", func(t *testing.T) {
+		filter := FilterBreadcrumbURLs(regexp.MustCompile(`/healthz$`), regexp.MustCompile(`^https://metrics\.`))
+
+		assert.Nil(t, filter(&BreadcrumbInfo{Data: map[string]interface{}{"url": "https://example.com/healthz"}}), "it should drop breadcrumbs with a matching URL")
+		assert.Nil(t, filter(&BreadcrumbInfo{Data: map[string]interface{}{"url": "https://metrics.example.com/"}}), "it should check every pattern")
+		assert.NotNil(t, filter(&BreadcrumbInfo{Data: map[string]interface{}{"url": "https://example.com/api"}}), "it should keep breadcrumbs with other URLs")
+		assert.NotNil(t, filter(&BreadcrumbInfo{}), "it should keep breadcrumbs without a URL")
+	})
+
+	t.Run("Capture()", func(t *testing.T) {
+		l := NewBreadcrumbsList(10).WithBeforeBreadcrumb(FilterBreadcrumbCategories("health"))
+		l.NewDefault(nil).WithCategory("health")
+		l.NewDefault(nil).WithCategory("app")
+
+		p := NewPacket().SetOptions(Breadcrumbs(l))
+		crumbs, ok := p.Get("breadcrumbs").(*breadcrumbsSnapshot)
+		require.True(t, ok, "the packet should include a snapshot of the breadcrumbs")
+		require.Len(t, crumbs.breadcrumbs, 1, "the filtered breadcrumb should not be included")
+		assert.Equal(t, "app", crumbs.breadcrumbs[0].(*breadcrumb).Category, "the remaining breadcrumb should be included")
+	})
+
+	t.Run("clone()", func(t *testing.T) {
+		l := NewBreadcrumbsList(10).WithBeforeBreadcrumb(FilterBreadcrumbCategories("health"))
+		c := l.(*breadcrumbsList).clone()
+		assert.Len(t, c.beforeBreadcrumb, 1, "the clone should include the list's hooks")
+
+		c.WithBeforeBreadcrumb(FilterBreadcrumbLevel(Error))
+		assert.Len(t, l.(*breadcrumbsList).beforeBreadcrumb, 1, "adding hooks to the clone should not affect the original")
+	})
+}
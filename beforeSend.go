@@ -0,0 +1,60 @@
+package sentry
+
+// ErrEventDropped is used when an event is not sent to Sentry because
+// a BeforeSend hook decided to drop it.
+const ErrEventDropped = ErrType("sentry: event was dropped")
+
+// BeforeSend allows you to register a hook which will be called with every
+// event captured by a client, once all of the client's and event's options
+// have been applied. The hook may modify the packet, replace it with a new
+// one or return nil to prevent the event from being sent, in which case
+// the QueuedEvent will fail with ErrEventDropped.
+//
+// Hooks may be provided to a client or passed to Capture() for a single
+// event. If multiple hooks are configured they will be called in the order
+// that they were added, with each receiving the packet returned by the last.
+func BeforeSend(hook func(packet Packet) Packet) Option {
+	if hook == nil {
+		return nil
+	}
+
+	return &beforeSendOption{hook}
+}
+
+type beforeSendOption struct {
+	hook func(packet Packet) Packet
+}
+
+func (o *beforeSendOption) Class() string {
+	return "sentry-go.beforesend"
+}
+
+func (o *beforeSendOption) Omit() bool {
+	return true
+}
+
+func (o *beforeSendOption) Merge(old Option) Option {
+	if old, ok := old.(*beforeSendOption); ok {
+		return &beforeSendOption{func(p Packet) Packet {
+			if p = old.hook(p); p == nil {
+				return nil
+			}
+
+			return o.hook(p)
+		}}
+	}
+
+	return o
+}
+
+// applyBeforeSend runs the client's BeforeSend hooks, followed by any which
+// were provided for the event, against a packet, returning nil if the packet
+// should be dropped.
+func (c *client) applyBeforeSend(p Packet, options []Option) Packet {
+	opt, ok := c.getEventOption("sentry-go.beforesend", options).(*beforeSendOption)
+	if !ok {
+		return p
+	}
+
+	return opt.hook(p)
+}
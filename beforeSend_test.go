@@ -0,0 +1,140 @@
+package sentry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleBeforeSend() {
+	cl := NewClient(
+		// You can use a BeforeSend hook to modify events before they
+		// are sent to Sentry
+		BeforeSend(func(packet Packet) Packet {
+			return packet.SetOptions(
+				Unset("server_name"),
+				Tags(map[string]string{"scrubbed": "true"}),
+			)
+		}),
+	)
+
+	e := cl.With(
+		// Or to drop them entirely by returning nil
+		BeforeSend(func(packet Packet) Packet {
+			return nil
+		}),
+	).Capture(Message("This event will never be sent"))
+
+	if ErrEventDropped.IsInstance(e.Error()) {
+		// The event was dropped by the BeforeSend hook
+	}
+}
+
+func TestBeforeSend(t *testing.T) {
+	assert.Nil(t, BeforeSend(nil), "it should return nil if the hook is nil")
+
+	hook := func(p Packet) Packet {
+		return p.SetOptions(Message("hooked"))
+	}
+
+	o := BeforeSend(hook)
+	require.NotNil(t, o, "it should not return nil if the hook is non-nil")
+	assert.Implements(t, (*Option)(nil), o, "it should implement the Option interface")
+	assert.Equal(t, "sentry-go.beforesend", o.Class(), "it should use the right option class")
+
+	if assert.Implements(t, (*OmitableOption)(nil), o, "it should implement the OmitableOption interface") {
+		assert.True(t, o.(OmitableOption).Omit(), "it should always return true for calls to Omit()")
+	}
+
+	if assert.Implements(t, (*MergeableOption)(nil), o, "it should implement the MergeableOption interface") {
+		t.Run("Merge()", func(t *testing.T) {
+			om := o.(MergeableOption)
+			assert.Equal(t, o, om.Merge(&testOption{}), "it should replace the old option if it is not recognized")
+
+			calls := []string{}
+			first := BeforeSend(func(p Packet) Packet {
+				calls = append(calls, "first")
+				return p
+			})
+
+			second := BeforeSend(func(p Packet) Packet {
+				calls = append(calls, "second")
+				return p
+			})
+
+			merged, ok := second.(MergeableOption).Merge(first).(*beforeSendOption)
+			require.True(t, ok, "it should actually be a *beforeSendOption")
+
+			p := NewPacket()
+			assert.Equal(t, p, merged.hook(p), "it should return the packet from the last hook")
+			assert.Equal(t, []string{"first", "second"}, calls, "it should call the hooks in the order they were added")
+
+			dropped := BeforeSend(func(p Packet) Packet {
+				calls = append(calls, "dropped")
+				return nil
+			})
+
+			calls = []string{}
+			merged = second.(MergeableOption).Merge(dropped).(*beforeSendOption)
+			assert.Nil(t, merged.hook(p), "it should return nil if an earlier hook dropped the packet")
+			assert.Equal(t, []string{"dropped"}, calls, "it should not call later hooks once the packet has been dropped")
+		})
+	}
+
+	t.Run("Capture()", func(t *testing.T) {
+		tr := testNewTestTransport()
+		q := NewSequentialSendQueue(10)
+		defer q.Shutdown(true)
+
+		cl := NewClient(UseTransport(tr), UseSendQueue(q))
+
+		t.Run("Modified", func(t *testing.T) {
+			var received Packet
+			e := cl.With(BeforeSend(func(p Packet) Packet {
+				received = p.Clone()
+				return p.SetOptions(Tags(map[string]string{"hooked": "true"}))
+			})).Capture(Message("test"), Tags(map[string]string{"event": "true"}))
+
+			p := <-tr.ch
+			assert.Nil(t, e.Error(), "the event should have been sent")
+
+			require.NotNil(t, received, "the hook should have been called")
+			assert.Equal(t, map[string]interface{}{
+				"hooked": "true",
+				"event":  "true",
+			}, testSerializePacket(t, p).(map[string]interface{})["tags"], "the modified packet should be sent")
+
+			data := testSerializePacket(t, received).(map[string]interface{})
+			assert.Equal(t, map[string]interface{}{"message": "test"}, data["sentry.interfaces.Message"], "the hook should receive the event's options")
+			assert.NotContains(t, data, "sentry-go.beforesend", "the hook should not be serialized")
+		})
+
+		t.Run("Dropped", func(t *testing.T) {
+			e := cl.With(BeforeSend(func(p Packet) Packet {
+				return nil
+			})).Capture(Message("test"))
+
+			require.NotNil(t, e, "the event should not be nil")
+			assert.NotEmpty(t, e.EventID(), "the event should still have an ID")
+			assert.True(t, ErrEventDropped.IsInstance(e.Error()), "the event should fail with ErrEventDropped")
+			assert.Equal(t, 0, e.(AttemptCountingEvent).Attempts(), "no attempts should have been made to send the event")
+		})
+
+		t.Run("Event Hook", func(t *testing.T) {
+			calls := []string{}
+			cl := cl.With(BeforeSend(func(p Packet) Packet {
+				calls = append(calls, "client")
+				return p
+			}))
+
+			e := cl.Capture(Message("test"), BeforeSend(func(p Packet) Packet {
+				calls = append(calls, "event")
+				return nil
+			}))
+
+			assert.True(t, ErrEventDropped.IsInstance(e.Error()), "the event should be dropped by its own hook")
+			assert.Equal(t, []string{"client", "event"}, calls, "the event's hook should run after the client's hooks")
+		})
+	})
+}
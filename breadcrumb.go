@@ -26,14 +26,14 @@ func newBreadcrumb(typename string, data map[string]interface{}) *breadcrumb {
 	}
 
 	return &breadcrumb{
-		Timestamp: time.Now().UTC().Unix(),
+		Timestamp: time.Now().UTC(),
 		Type:      typename,
 		Data:      data,
 	}
 }
 
 type breadcrumb struct {
-	Timestamp int64                  `json:"timestamp"`
+	Timestamp time.Time              `json:"timestamp"`
 	Type      string                 `json:"type,omitempty"`
 	Message   string                 `json:"message,omitempty"`
 	Data      map[string]interface{} `json:"data,omitempty"`
@@ -41,6 +41,21 @@ type breadcrumb struct {
 	Level     Severity               `json:"level,omitempty"`
 }
 
+// clone creates a copy of this breadcrumb which will not be affected by
+// any later changes to it.
+func (b *breadcrumb) clone() *breadcrumb {
+	c := *b
+
+	if b.Data != nil {
+		c.Data = make(map[string]interface{}, len(b.Data))
+		for k, v := range b.Data {
+			c.Data[k] = v
+		}
+	}
+
+	return &c
+}
+
 func (b *breadcrumb) WithMessage(msg string) Breadcrumb {
 	b.Message = msg
 	return b
@@ -52,7 +67,7 @@ func (b *breadcrumb) WithCategory(cat string) Breadcrumb {
 }
 
 func (b *breadcrumb) WithTimestamp(ts time.Time) Breadcrumb {
-	b.Timestamp = ts.UTC().Unix()
+	b.Timestamp = ts.UTC()
 	return b
 }
 
@@ -1,6 +1,7 @@
 package sentry
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -42,7 +43,7 @@ func TestBreadcrumb(t *testing.T) {
 		if assert.NotNil(t, b) {
 			assert.Implements(t, (*Breadcrumb)(nil), b)
 			assert.Equal(t, "", b.Type, "It should set the correct type")
-			assert.NotEqual(t, 0, b.Timestamp, "It should set the timestamp")
+			assert.False(t, b.Timestamp.IsZero(), "It should set the timestamp")
 			assert.Equal(t, data, b.Data, "It should set the correct data")
 		}
 	})
@@ -84,7 +85,33 @@ func TestBreadcrumb(t *testing.T) {
 		if assert.NotNil(t, b) {
 			bb := b.WithTimestamp(now)
 			assert.Equal(t, b, bb, "It should return the breadcrumb for chaining")
-			assert.Equal(t, now.UTC().Unix(), b.Timestamp)
+			assert.Equal(t, now.UTC(), b.Timestamp, "It should preserve sub-second precision")
 		}
 	})
+
+	t.Run("MarshalJSON()", func(t *testing.T) {
+		b := newBreadcrumb("default", nil)
+		b.WithTimestamp(time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.FixedZone("UTC+2", 2*60*60)))
+
+		var data map[string]interface{}
+		bytes, err := json.Marshal(b)
+		assert.Nil(t, err, "It should serialize without an error")
+		assert.Nil(t, json.Unmarshal(bytes, &data), "It should produce valid JSON")
+		assert.Equal(t, "2020-01-02T01:04:05.123456Z", data["timestamp"], "It should use an RFC3339 timestamp with fractional seconds")
+	})
+	t.Run("clone()", func(t *testing.T) {
+		b := newBreadcrumb("default", map[string]interface{}{"test": true})
+		b.WithMessage("original")
+
+		c := b.clone()
+		assert.False(t, b == c, "it should return a new breadcrumb")
+		assert.Equal(t, b, c, "it should copy the breadcrumb's fields")
+
+		b.WithMessage("modified")
+		b.Data["test"] = false
+		assert.Equal(t, "original", c.Message, "it should not be affected by changes to the original")
+		assert.Equal(t, true, c.Data["test"], "it should not be affected by changes to the original's data")
+
+		assert.Nil(t, (&breadcrumb{}).clone().Data, "it should not create data if the original had none")
+	})
 }
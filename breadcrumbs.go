@@ -2,7 +2,9 @@ package sentry
 
 import (
 	"encoding/json"
+	"reflect"
 	"sync"
+	"time"
 )
 
 var globalBreadcrumbs = NewBreadcrumbsList(10)
@@ -49,6 +51,49 @@ type BreadcrumbsList interface {
 	// NewHTTPRequest creates a new HTTP request breadcrumb which
 	// describes the results of an HTTP request.
 	NewHTTPRequest(method, url string, statusCode int, reason string) Breadcrumb
+
+	// NewQuery creates a new query breadcrumb which describes a query
+	// made against a database, along with how long it took to complete.
+	NewQuery(query string, duration time.Duration) Breadcrumb
+
+	// NewUser creates a new user breadcrumb which represents an action
+	// taken by the user of your application, like clicking a button.
+	NewUser(data map[string]interface{}) Breadcrumb
+
+	// NewError creates a new error breadcrumb which describes an error
+	// that occurred, but was not reported to Sentry in its own right.
+	NewError(err error) Breadcrumb
+
+	// NewDebug creates a new debug breadcrumb, which is useful for
+	// recording diagnostic information.
+	NewDebug(data map[string]interface{}) Breadcrumb
+
+	// NewInfo creates a new info breadcrumb, which is useful for
+	// recording information about the normal operation of your
+	// application.
+	NewInfo(data map[string]interface{}) Breadcrumb
+
+	// WithBeforeBreadcrumb registers a hook which will be called for each
+	// breadcrumb in this list when it is included in an event. The hook
+	// may modify the breadcrumb or return nil to drop it. Hooks are called
+	// in the order they were added, with each receiving the breadcrumb
+	// returned by the last.
+	//
+	// Hooks run when an event is captured, rather than when a breadcrumb
+	// is created, so that they see any changes made using its WithX()
+	// methods. The breadcrumbs held by the list are never modified, which
+	// means that dropped breadcrumbs still count towards its size.
+	WithBeforeBreadcrumb(hook func(crumb *BreadcrumbInfo) *BreadcrumbInfo) BreadcrumbsList
+
+	// NewChild creates a new list, holding up to size breadcrumbs, which
+	// starts with a copy of the breadcrumbs in this list. Breadcrumbs which
+	// are added to the child are not added to this list and vice versa,
+	// making it useful for keeping track of a single request while still
+	// including the application's history leading up to it. The inherited
+	// breadcrumbs are always included and do not count towards the size.
+	//
+	// The child will use the same BeforeBreadcrumb hooks as this list.
+	NewChild(size int) BreadcrumbsList
 }
 
 // NewBreadcrumbsList will create a new BreadcrumbsList which can be
@@ -67,6 +112,13 @@ type breadcrumbsList struct {
 	Tail   *breadcrumbListNode
 	Length int
 	mutex  sync.RWMutex
+
+	// inherited holds the breadcrumbs copied from the list this one was
+	// created from by NewChild. They come before the list's own breadcrumbs
+	// and are not counted towards its MaxLength.
+	inherited []Breadcrumb
+
+	beforeBreadcrumb []func(crumb *BreadcrumbInfo) *BreadcrumbInfo
 }
 
 func (l *breadcrumbsList) Class() string {
@@ -83,6 +135,7 @@ func (l *breadcrumbsList) WithSize(length int) BreadcrumbsList {
 		l.Head = nil
 		l.Tail = nil
 		l.Length = 0
+		l.inherited = nil
 	}
 
 	for l.Length > l.MaxLength && l.Head != nil {
@@ -113,12 +166,131 @@ func (l *breadcrumbsList) NewNavigation(from, to string) Breadcrumb {
 }
 
 func (l *breadcrumbsList) NewHTTPRequest(method, url string, statusCode int, reason string) Breadcrumb {
-	b := newBreadcrumb("http", map[string]interface{}{
+	b := newHTTPRequestBreadcrumb(method, url, statusCode, reason)
+	l.append(b)
+	return b
+}
+
+// newHTTPRequestBreadcrumb creates a breadcrumb describing the results of
+// an HTTP request, without adding it to a list.
+func newHTTPRequestBreadcrumb(method, url string, statusCode int, reason string) *breadcrumb {
+	return newBreadcrumb("http", map[string]interface{}{
 		"method":      method,
 		"url":         url,
 		"status_code": statusCode,
 		"reason":      reason,
 	})
+}
+
+// clone creates a copy of this list which can be modified independently.
+func (l *breadcrumbsList) clone() *breadcrumbsList {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	nl := &breadcrumbsList{
+		MaxLength: l.MaxLength,
+		inherited: cloneBreadcrumbs(l.inherited),
+
+		beforeBreadcrumb: append([]func(crumb *BreadcrumbInfo) *BreadcrumbInfo{}, l.beforeBreadcrumb...),
+	}
+
+	for current := l.Head; current != nil; current = current.Next {
+		nl.append(cloneBreadcrumb(current.Value))
+	}
+
+	return nl
+}
+
+func (l *breadcrumbsList) NewChild(size int) BreadcrumbsList {
+	l.mutex.RLock()
+	nl := &breadcrumbsList{
+		MaxLength: size,
+
+		beforeBreadcrumb: append([]func(crumb *BreadcrumbInfo) *BreadcrumbInfo{}, l.beforeBreadcrumb...),
+	}
+	l.mutex.RUnlock()
+
+	// The inherited breadcrumbs are copied so that they cannot be changed
+	// through either list, and are kept apart from the child's own so that
+	// they don't count towards its size.
+	if size != 0 {
+		nl.inherited = cloneBreadcrumbs(l.list())
+	}
+
+	return nl
+}
+
+// cloneBreadcrumb copies a breadcrumb so that it will not be affected by
+// any later changes to the original.
+func cloneBreadcrumb(b Breadcrumb) Breadcrumb {
+	if bb, ok := b.(*breadcrumb); ok {
+		return bb.clone()
+	}
+
+	return b
+}
+
+// cloneBreadcrumbs copies each of the breadcrumbs in a list.
+func cloneBreadcrumbs(crumbs []Breadcrumb) []Breadcrumb {
+	out := make([]Breadcrumb, 0, len(crumbs))
+	for _, b := range crumbs {
+		out = append(out, cloneBreadcrumb(b))
+	}
+
+	return out
+}
+
+func (l *breadcrumbsList) NewQuery(query string, duration time.Duration) Breadcrumb {
+	b := newBreadcrumb("query", map[string]interface{}{
+		"duration_ms": float64(duration) / float64(time.Millisecond),
+	})
+	b.Message = query
+	b.Category = "query"
+	l.append(b)
+	return b
+}
+
+func (l *breadcrumbsList) NewUser(data map[string]interface{}) Breadcrumb {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	b := newBreadcrumb("user", data)
+	l.append(b)
+	return b
+}
+
+func (l *breadcrumbsList) NewError(err error) Breadcrumb {
+	b := newBreadcrumb("error", map[string]interface{}{})
+	b.Level = Error
+
+	if err != nil {
+		b.Message = err.Error()
+		b.Data["type"] = reflect.TypeOf(err).String()
+	}
+
+	l.append(b)
+	return b
+}
+
+func (l *breadcrumbsList) NewDebug(data map[string]interface{}) Breadcrumb {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	b := newBreadcrumb("debug", data)
+	b.Level = Debug
+	l.append(b)
+	return b
+}
+
+func (l *breadcrumbsList) NewInfo(data map[string]interface{}) Breadcrumb {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	b := newBreadcrumb("info", data)
+	b.Level = Info
 	l.append(b)
 	return b
 }
@@ -127,6 +299,36 @@ func (l *breadcrumbsList) MarshalJSON() ([]byte, error) {
 	return json.Marshal(l.list())
 }
 
+// Apply adds a snapshot of the list's current breadcrumbs to a packet,
+// ensuring that breadcrumbs which are recorded after an event has been
+// captured are not included in it.
+func (l *breadcrumbsList) Apply(packet map[string]Option) {
+	packet[l.Class()] = l.snapshot()
+}
+
+// snapshot creates an immutable copy of the breadcrumbs in this list,
+// once they have been passed through its BeforeBreadcrumb hooks.
+func (l *breadcrumbsList) snapshot() *breadcrumbsSnapshot {
+	l.mutex.RLock()
+	hooks := l.beforeBreadcrumb
+	l.mutex.RUnlock()
+
+	crumbs := []Breadcrumb{}
+	for _, b := range l.list() {
+		if bb, ok := b.(*breadcrumb); ok {
+			if bb = applyBeforeBreadcrumb(hooks, bb.clone()); bb == nil {
+				continue
+			}
+
+			b = bb
+		}
+
+		crumbs = append(crumbs, b)
+	}
+
+	return &breadcrumbsSnapshot{crumbs}
+}
+
 func (l *breadcrumbsList) append(b Breadcrumb) {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
@@ -161,7 +363,7 @@ func (l *breadcrumbsList) list() []Breadcrumb {
 	defer l.mutex.RUnlock()
 
 	current := l.Head
-	out := []Breadcrumb{}
+	out := append([]Breadcrumb{}, l.inherited...)
 	for current != nil {
 		out = append(out, current.Value)
 		current = current.Next
@@ -170,6 +372,20 @@ func (l *breadcrumbsList) list() []Breadcrumb {
 	return out
 }
 
+// breadcrumbsSnapshot holds the breadcrumbs which were present in a list
+// at the time an event was captured.
+type breadcrumbsSnapshot struct {
+	breadcrumbs []Breadcrumb
+}
+
+func (s *breadcrumbsSnapshot) Class() string {
+	return "breadcrumbs"
+}
+
+func (s *breadcrumbsSnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.breadcrumbs)
+}
+
 type breadcrumbListNode struct {
 	Next  *breadcrumbListNode
 	Value Breadcrumb
@@ -1,9 +1,13 @@
 package sentry
 
 import (
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func ExampleDefaultBreadcrumbs() {
@@ -30,6 +34,14 @@ func ExampleDefaultBreadcrumbs() {
 		WithLevel(Debug).
 		WithMessage("I think we can agree that they're pretty awesome")
 
+	DefaultBreadcrumbs().NewQuery("SELECT * FROM users WHERE id = ?", 12*time.Millisecond)
+	DefaultBreadcrumbs().NewUser(map[string]interface{}{
+		"button": "checkout",
+	}).WithMessage("You can record the actions your users take")
+	DefaultBreadcrumbs().NewInfo(map[string]interface{}{
+		"items": 3,
+	}).WithMessage("Along with what your application was doing at the time")
+
 	NewClient().Capture(Message("Finally, we send the event with all our breadcrumbs included"))
 }
 
@@ -54,6 +66,21 @@ func ExampleBreadcrumbs() {
 	)
 }
 
+func ExampleBreadcrumbsList_NewChild() {
+	cl := NewClient()
+
+	http.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		// Each request gets its own breadcrumbs, starting with the
+		// application's history up to this point.
+		breadcrumbs := DefaultBreadcrumbs().NewChild(20)
+		cl := cl.With(Breadcrumbs(breadcrumbs))
+
+		breadcrumbs.NewInfo(nil).WithMessage("Handling request")
+
+		cl.Capture(Message("This event only includes breadcrumbs from this request"))
+	})
+}
+
 func TestBreadcrumbs(t *testing.T) {
 	t.Run("Options Providers", func(t *testing.T) {
 		assert.NotNil(t, testGetOptionsProvider(t, &breadcrumbsList{}), "Breadcrumbs should be registered as a default options provider")
@@ -159,6 +186,142 @@ func TestNewBreadcrumbsList(t *testing.T) {
 		}, bb.Data, "it should use the correct breadcrumb data")
 	})
 
+	t.Run("NewQuery()", func(t *testing.T) {
+		b := l.NewQuery("SELECT * FROM users", 1500*time.Microsecond)
+		assert.NotNil(t, b, "it should return a non-nil breadcrumb")
+		assert.Equal(t, ll.Tail.Value, b, "the list's tail should now be the new breadcrumb")
+
+		bb, ok := b.(*breadcrumb)
+		assert.True(t, ok, "it should actually be a *breadcrumb object")
+		assert.Equal(t, "query", bb.Type, "it should use the query breadcrumb type")
+		assert.Equal(t, "query", bb.Category, "it should use the query category")
+		assert.Equal(t, "SELECT * FROM users", bb.Message, "it should use the query as the message")
+		assert.Equal(t, map[string]interface{}{
+			"duration_ms": 1.5,
+		}, bb.Data, "it should include the query's duration")
+	})
+
+	t.Run("NewUser()", func(t *testing.T) {
+		b := l.NewUser(nil)
+		assert.Equal(t, ll.Tail.Value, b, "the list's tail should now be the new breadcrumb")
+
+		bb, ok := b.(*breadcrumb)
+		assert.True(t, ok, "it should actually be a *breadcrumb object")
+		assert.Equal(t, "user", bb.Type, "it should use the user breadcrumb type")
+		assert.Equal(t, map[string]interface{}{}, bb.Data, "it should use empty data if none is provided")
+
+		data := map[string]interface{}{"button": "submit"}
+		bb = l.NewUser(data).(*breadcrumb)
+		assert.Equal(t, data, bb.Data, "it should use the passed breadcrumb data")
+	})
+
+	t.Run("NewError()", func(t *testing.T) {
+		b := l.NewError(fmt.Errorf("example error"))
+		assert.Equal(t, ll.Tail.Value, b, "the list's tail should now be the new breadcrumb")
+
+		bb, ok := b.(*breadcrumb)
+		assert.True(t, ok, "it should actually be a *breadcrumb object")
+		assert.Equal(t, "error", bb.Type, "it should use the error breadcrumb type")
+		assert.Equal(t, Error, bb.Level, "it should use the error level")
+		assert.Equal(t, "example error", bb.Message, "it should use the error's message")
+		assert.Equal(t, map[string]interface{}{
+			"type": "*errors.errorString",
+		}, bb.Data, "it should include the error's type")
+
+		bb = l.NewError(nil).(*breadcrumb)
+		assert.Equal(t, "", bb.Message, "it should handle nil errors")
+		assert.Equal(t, map[string]interface{}{}, bb.Data, "it should not include a type for nil errors")
+	})
+
+	t.Run("NewDebug()", func(t *testing.T) {
+		data := map[string]interface{}{"cache": "miss"}
+		b := l.NewDebug(data)
+		assert.Equal(t, ll.Tail.Value, b, "the list's tail should now be the new breadcrumb")
+
+		bb, ok := b.(*breadcrumb)
+		assert.True(t, ok, "it should actually be a *breadcrumb object")
+		assert.Equal(t, "debug", bb.Type, "it should use the debug breadcrumb type")
+		assert.Equal(t, Debug, bb.Level, "it should use the debug level")
+		assert.Equal(t, data, bb.Data, "it should use the passed breadcrumb data")
+
+		assert.Equal(t, map[string]interface{}{}, l.NewDebug(nil).(*breadcrumb).Data, "it should use empty data if none is provided")
+	})
+
+	t.Run("NewInfo()", func(t *testing.T) {
+		data := map[string]interface{}{"job": "cleanup"}
+		b := l.NewInfo(data)
+		assert.Equal(t, ll.Tail.Value, b, "the list's tail should now be the new breadcrumb")
+
+		bb, ok := b.(*breadcrumb)
+		assert.True(t, ok, "it should actually be a *breadcrumb object")
+		assert.Equal(t, "info", bb.Type, "it should use the info breadcrumb type")
+		assert.Equal(t, Info, bb.Level, "it should use the info level")
+		assert.Equal(t, data, bb.Data, "it should use the passed breadcrumb data")
+
+		assert.Equal(t, map[string]interface{}{}, l.NewInfo(nil).(*breadcrumb).Data, "it should use empty data if none is provided")
+	})
+
+	t.Run("NewChild()", func(t *testing.T) {
+		parent := NewBreadcrumbsList(10).WithBeforeBreadcrumb(FilterBreadcrumbCategories("health"))
+		parent.NewDefault(nil).WithMessage("before")
+
+		child := parent.NewChild(2)
+		require.NotNil(t, child, "it should return a non-nil list")
+
+		cl, ok := child.(*breadcrumbsList)
+		require.True(t, ok, "it should actually be a *breadcrumbsList")
+		assert.Equal(t, 2, cl.MaxLength, "it should use the provided size")
+		assert.Len(t, cl.beforeBreadcrumb, 1, "it should inherit the parent's hooks")
+
+		messagesOf := func(l BreadcrumbsList) []string {
+			messages := []string{}
+			for _, b := range l.(*breadcrumbsList).list() {
+				messages = append(messages, b.(*breadcrumb).Message)
+			}
+
+			return messages
+		}
+
+		assert.Equal(t, []string{"before"}, messagesOf(child), "it should inherit the parent's breadcrumbs")
+
+		parent.NewDefault(nil).WithMessage("parent")
+		child.NewDefault(nil).WithMessage("child")
+		assert.Equal(t, []string{"before", "parent"}, messagesOf(parent), "breadcrumbs added to the child should not be added to the parent")
+		assert.Equal(t, []string{"before", "child"}, messagesOf(child), "breadcrumbs added to the parent should not be added to the child")
+
+		parent.(*breadcrumbsList).list()[0].WithMessage("modified")
+		assert.Equal(t, "before", messagesOf(child)[0], "inherited breadcrumbs should not be affected by changes to the parent's")
+
+		child.NewDefault(nil).WithMessage("another")
+		assert.Equal(t, []string{"before", "child", "another"}, messagesOf(child), "the inherited breadcrumbs should not count towards the child's size")
+
+		child.NewDefault(nil).WithMessage("last")
+		assert.Equal(t, []string{"before", "another", "last"}, messagesOf(child), "the child's own breadcrumbs should be removed first")
+
+		assert.Empty(t, messagesOf(parent.NewChild(0)), "it should not inherit breadcrumbs if the child is disabled")
+
+		child.WithSize(0)
+		assert.Empty(t, messagesOf(child), "disabling the child should remove its inherited breadcrumbs")
+	})
+
+	t.Run("clone()", func(t *testing.T) {
+		parent := NewBreadcrumbsList(10)
+		parent.NewDefault(nil).WithMessage("inherited")
+
+		l := parent.NewChild(1)
+		l.NewDefault(nil).WithMessage("own")
+
+		c := l.(*breadcrumbsList).clone()
+		assert.Equal(t, 1, c.MaxLength, "it should use the same size")
+		assert.Equal(t, l.(*breadcrumbsList).list(), c.list(), "it should copy the list's breadcrumbs")
+
+		c.NewDefault(nil).WithMessage("clone")
+		require.Len(t, c.list(), 2, "the inherited breadcrumbs should not count towards the clone's size")
+		assert.Equal(t, "inherited", c.list()[0].(*breadcrumb).Message, "it should keep the inherited breadcrumbs")
+		assert.Equal(t, "clone", c.list()[1].(*breadcrumb).Message, "it should replace the list's own breadcrumbs")
+		assert.Equal(t, "own", l.(*breadcrumbsList).list()[1].(*breadcrumb).Message, "it should not modify the original list")
+	})
+
 	t.Run("WithSize()", func(t *testing.T) {
 		cl := l.WithSize(5)
 		assert.Equal(t, l, cl, "it should return the list so that the call is chainable")
@@ -217,6 +380,78 @@ func TestNewBreadcrumbsList(t *testing.T) {
 		}
 	})
 
+	t.Run("Apply()", func(t *testing.T) {
+		l.WithSize(0).WithSize(5)
+		assert.Implements(t, (*AdvancedOption)(nil), l, "it should implement the AdvancedOption interface")
+
+		b := l.NewDefault(map[string]interface{}{"index": 0}).WithMessage("original")
+
+		p := NewPacket().SetOptions(Breadcrumbs(l))
+		snapshot, ok := p.Get("breadcrumbs").(*breadcrumbsSnapshot)
+		require.True(t, ok, "it should add a snapshot of the breadcrumbs to the packet")
+		assert.Equal(t, "breadcrumbs", snapshot.Class(), "the snapshot should use the correct option class")
+		require.Len(t, snapshot.breadcrumbs, 1, "the snapshot should include the list's breadcrumbs")
+
+		l.NewDefault(map[string]interface{}{"index": 1})
+		b.WithMessage("modified")
+		b.(*breadcrumb).Data["index"] = 2
+
+		assert.Len(t, snapshot.breadcrumbs, 1, "the snapshot should not include breadcrumbs added later")
+		assert.Equal(t, "original", snapshot.breadcrumbs[0].(*breadcrumb).Message, "the snapshot should not be affected by changes to its breadcrumbs")
+		assert.Equal(t, 0, snapshot.breadcrumbs[0].(*breadcrumb).Data["index"], "the snapshot should not be affected by changes to its breadcrumbs' data")
+
+		assert.Equal(t, testOptionsSerialize(t, snapshot), testSerializePacket(t, p).(map[string]interface{})["breadcrumbs"], "the snapshot should be serialized as a list of breadcrumbs")
+	})
+
+	t.Run("Capture()", func(t *testing.T) {
+		l := NewBreadcrumbsList(10)
+
+		tr := testNewBlockingTransport()
+		defer tr.Release()
+
+		q := NewSequentialSendQueue(10)
+		defer q.Shutdown(true)
+
+		sent := []Packet{}
+		cl := NewClient(
+			UseSendQueue(q),
+			UseTransport(tr),
+			Breadcrumbs(l),
+			BeforeSend(func(p Packet) Packet {
+				sent = append(sent, p)
+				return p
+			}),
+		)
+
+		l.NewDefault(nil).WithMessage("first")
+		e1 := cl.Capture(Message("event 1"))
+
+		l.NewDefault(nil).WithMessage("second")
+		e2 := cl.Capture(Message("event 2"))
+
+		l.NewDefault(nil).WithMessage("third")
+
+		// Give the queue the chance to serialize the events
+		time.Sleep(10 * time.Millisecond)
+		tr.Release()
+
+		assert.Nil(t, e1.Error(), "the first event should have been sent")
+		assert.Nil(t, e2.Error(), "the second event should have been sent")
+
+		messages := func(p Packet) []string {
+			out := []string{}
+			for _, b := range p.Get("breadcrumbs").(*breadcrumbsSnapshot).breadcrumbs {
+				out = append(out, b.(*breadcrumb).Message)
+			}
+
+			return out
+		}
+
+		require.Len(t, sent, 2, "both events should have been captured")
+		assert.Equal(t, []string{"first"}, messages(sent[0]), "the first event should only include the breadcrumbs recorded before it was captured")
+		assert.Equal(t, []string{"first", "second"}, messages(sent[1]), "the second event should only include the breadcrumbs recorded before it was captured")
+	})
+
 	t.Run("MarshalJSON()", func(t *testing.T) {
 		l.WithSize(0).WithSize(5).NewDefault(map[string]interface{}{"test": true})
 
@@ -1,5 +1,11 @@
 package sentry
 
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
 // A Client is responsible for letting you interact with the Sentry API.
 // You can create derivative clients
 type Client interface {
@@ -20,6 +26,43 @@ type Client interface {
 	Capture(options ...Option) QueuedEvent
 }
 
+// A RecoveringClient is a Client which is able to report panics to Sentry.
+// The clients created by NewClient implement it, which you can check with
+// a type assertion.
+type RecoveringClient interface {
+	Client
+
+	// Recover should be deferred at the start of a function to capture
+	// any panics which occur within it, reporting them to Sentry as an
+	// unhandled exception. It waits for the event to be sent before it
+	// returns, up to the RecoverTimeout, and will re-panic with the
+	// original value if RecoverRepanic(true) has been set.
+	Recover(options ...Option)
+
+	// CapturePanic allows you to report a value which you have retrieved
+	// using recover() to Sentry as an unhandled exception. It should be
+	// called from the deferred function which recovered the panic so that
+	// the stack of the panicking goroutine can be included.
+	CapturePanic(recovered interface{}, options ...Option) QueuedEvent
+}
+
+// A FlushableClient is a Client which is able to wait for the events it
+// has queued to be sent. The clients created by NewClient implement it,
+// which you can check with a type assertion.
+type FlushableClient interface {
+	Client
+
+	// Flush waits until all of the events which have been queued on this
+	// client's SendQueue have been sent, or the context expires. It is
+	// useful at the end of short-lived processes like CLI tools, Lambda
+	// invocations and tests, where you don't wish to shut the queue down.
+	// Keep in mind that the SendQueue may be shared with other clients,
+	// in which case their events will also be waited upon. If the
+	// SendQueue is not a FlushableSendQueue, ErrSendQueueNotFlushable
+	// is returned.
+	Flush(ctx context.Context) error
+}
+
 var defaultClient = NewClient()
 
 // DefaultClient is a singleton client instance which can be used instead
@@ -45,7 +88,28 @@ func NewClient(options ...Option) Client {
 func (c *client) Capture(options ...Option) QueuedEvent {
 	p := NewPacket().SetOptions(c.fullDefaultOptions()...).SetOptions(options...)
 
-	return c.SendQueue().Enqueue(c, p)
+	if !c.applySampler(p, options) {
+		err := errors.New("event was not selected by the sampler")
+		return newFailedEvent(c, p, errors.Wrap(err, ErrEventSampled.Error()))
+	}
+
+	sp := c.applyBeforeSend(p, options)
+	if sp == nil {
+		err := errors.New("BeforeSend hook returned nil")
+		return newFailedEvent(c, p, errors.Wrap(err, ErrEventDropped.Error()))
+	}
+
+	return c.SendQueue().Enqueue(c, sp)
+}
+
+func (c *client) Flush(ctx context.Context) error {
+	q, ok := c.SendQueue().(FlushableSendQueue)
+	if !ok {
+		err := errors.New("the send queue does not implement FlushableSendQueue")
+		return errors.Wrap(err, ErrSendQueueNotFlushable.Error())
+	}
+
+	return q.Flush(ctx)
 }
 
 func (c *client) With(options ...Option) Client {
@@ -77,6 +141,12 @@ func (c *client) GetOption(className string) Option {
 	return opt
 }
 
+// getEventOption retrieves a configuration option for an event, taking the
+// options which were provided when it was captured into account.
+func (c *client) getEventOption(className string, options []Option) Option {
+	return c.With(options...).GetOption(className)
+}
+
 func (c *client) DSN() string {
 	opt := c.GetOption("sentry-go.dsn")
 	if opt == nil {
@@ -0,0 +1,41 @@
+package sentry
+
+import "context"
+
+// clientContextKey is used to store a Client in a context.Context
+type clientContextKey struct{}
+
+// ContextWithClient returns a copy of the provided context which carries
+// the given client, allowing it to be retrieved using ClientFromContext()
+// by any code which the context is passed to.
+func ContextWithClient(ctx context.Context, cl Client) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, cl)
+}
+
+// ClientFromContext retrieves the client which has been attached to a
+// context using ContextWithClient() or ContextWithOptions(). If the context
+// does not carry a client then the DefaultClient() will be returned.
+func ClientFromContext(ctx context.Context) Client {
+	if ctx != nil {
+		if cl, ok := ctx.Value(clientContextKey{}).(Client); ok && cl != nil {
+			return cl
+		}
+	}
+
+	return DefaultClient()
+}
+
+// ContextWithOptions returns a copy of the provided context which carries
+// a client with the given options set as part of its defaults. The client
+// is derived from the one already attached to the context, so options
+// accumulate as the context is passed down your call chain.
+func ContextWithOptions(ctx context.Context, options ...Option) context.Context {
+	return ContextWithClient(ctx, ClientFromContext(ctx).With(options...))
+}
+
+// CaptureContext will capture an event using the client attached to the
+// provided context, including any options which have been added to it
+// using ContextWithOptions().
+func CaptureContext(ctx context.Context, options ...Option) QueuedEvent {
+	return ClientFromContext(ctx).Capture(options...)
+}
@@ -0,0 +1,90 @@
+package sentry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleContextWithOptions() {
+	// Attach options to your context as it is passed down your call chain
+	ctx := ContextWithOptions(context.Background(), Tags(map[string]string{
+		"request_id": "0e2a1d0c",
+	}))
+
+	ctx = ContextWithOptions(ctx, User(&UserInfo{
+		ID: "1234",
+	}))
+
+	// And they will be included in any events captured using that context
+	CaptureContext(ctx, Message("This event includes the request ID and user"))
+}
+
+func ExampleClientFromContext() {
+	ctx := ContextWithClient(context.Background(), NewClient(
+		Release("v1.0.0"),
+	))
+
+	// You can retrieve the client from the context wherever you need it
+	cl := ClientFromContext(ctx)
+	cl.Capture(Message("Sent using the context's client"))
+}
+
+func TestClientContext(t *testing.T) {
+	tr := &testFlakyTransport{}
+	q := NewSequentialSendQueue(10)
+	defer q.Shutdown(true)
+
+	cl := NewClient(UseTransport(tr), UseSendQueue(q))
+
+	t.Run("ClientFromContext()", func(t *testing.T) {
+		assert.Equal(t, DefaultClient(), ClientFromContext(context.Background()), "it should return the default client if the context has no client")
+		assert.Equal(t, DefaultClient(), ClientFromContext(nil), "it should return the default client if the context is nil")
+		assert.Equal(t, DefaultClient(), ClientFromContext(ContextWithClient(context.Background(), nil)), "it should return the default client if the context's client is nil")
+	})
+
+	t.Run("ContextWithClient()", func(t *testing.T) {
+		ctx := ContextWithClient(context.Background(), cl)
+		require.NotNil(t, ctx, "it should not return nil")
+		assert.Equal(t, cl, ClientFromContext(ctx), "it should attach the client to the context")
+
+		cl2 := NewClient()
+		assert.Equal(t, cl2, ClientFromContext(ContextWithClient(ctx, cl2)), "it should replace the client attached to the context")
+		assert.Equal(t, cl, ClientFromContext(ctx), "it should not modify the original context")
+	})
+
+	t.Run("ContextWithOptions()", func(t *testing.T) {
+		ctx := ContextWithClient(context.Background(), cl)
+		ctx1 := ContextWithOptions(ctx, Tags(map[string]string{"a": "1"}))
+		ctx2 := ContextWithOptions(ctx1, Tags(map[string]string{"b": "2"}))
+
+		tags, ok := ClientFromContext(ctx2).GetOption("tags").(*tagsOption)
+		require.True(t, ok, "the context's client should include the tags")
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, tags.tags, "the options should accumulate down the call chain")
+
+		tags, ok = ClientFromContext(ctx1).GetOption("tags").(*tagsOption)
+		require.True(t, ok, "the parent context's client should include its tags")
+		assert.Equal(t, map[string]string{"a": "1"}, tags.tags, "the parent context should not be modified")
+
+		assert.Nil(t, ClientFromContext(ctx).GetOption("tags"), "the original client should not be modified")
+
+		assert.NotNil(t, ClientFromContext(ContextWithOptions(context.Background(), Release("v1.0.0"))).GetOption("release"), "it should derive from the default client if the context has no client")
+	})
+
+	t.Run("CaptureContext()", func(t *testing.T) {
+		ctx := ContextWithOptions(ContextWithClient(context.Background(), cl), Tags(map[string]string{"a": "1"}))
+
+		e := CaptureContext(ctx, Message("test"), Tags(map[string]string{"b": "2"}))
+		require.NotNil(t, e, "it should return the queued event")
+		assert.Nil(t, e.Error(), "the event should have been sent")
+
+		tr.mutex.Lock()
+		defer tr.mutex.Unlock()
+
+		require.Len(t, tr.sent, 1, "the event should have been sent using the context's client")
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, tr.sent[0].Tags(), "the event should include the context's options")
+		assert.Equal(t, "test", tr.sent[0].Message(), "the event should include the provided options")
+	})
+}
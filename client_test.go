@@ -1,6 +1,7 @@
 package sentry
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -32,6 +33,22 @@ func ExampleClient() {
 	)
 }
 
+func ExampleFlushableClient() {
+	cl := NewClient()
+
+	cl.Capture(
+		Message("This is an example message"),
+	)
+
+	// Wait for up to 5 seconds for all queued events to be sent
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := cl.(FlushableClient).Flush(ctx); err != nil {
+		fmt.Println("failed to flush events: ", err)
+	}
+}
+
 func ExampleDefaultClient() {
 	DefaultClient().Capture(
 		Message("This is an example message"),
@@ -84,6 +101,30 @@ func TestNewClient(t *testing.T) {
 			}
 		})
 
+		t.Run("Flush()", func(t *testing.T) {
+			tr := testNewTestTransport()
+			q := NewSequentialSendQueue(10)
+			defer q.Shutdown(true)
+
+			cl := NewClient(UseTransport(tr), UseSendQueue(q))
+			e := cl.Capture(Message("test"))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			assert.Equal(t, context.DeadlineExceeded, cl.(FlushableClient).Flush(ctx), "it should return the context's error if it expires")
+
+			go func() {
+				<-tr.ch
+			}()
+
+			assert.Nil(t, cl.(FlushableClient).Flush(context.Background()), "it should wait for the event to be sent")
+			assert.Nil(t, e.Error(), "the event should have been sent")
+
+			cl = NewClient(UseSendQueue(&struct{ SendQueue }{q}))
+			err := cl.(FlushableClient).Flush(context.Background())
+			assert.True(t, ErrSendQueueNotFlushable.IsInstance(err), "it should return an error if the queue cannot be flushed")
+		})
+
 		t.Run("With()", func(t *testing.T) {
 			opt := &testOption{}
 
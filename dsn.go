@@ -52,10 +52,11 @@ const (
 )
 
 type dsn struct {
-	URL        string
-	PublicKey  string
-	PrivateKey string
-	ProjectID  string
+	URL         string
+	EnvelopeURL string
+	PublicKey   string
+	PrivateKey  string
+	ProjectID   string
 }
 
 func newDSN(url string) (*dsn, error) {
@@ -68,15 +69,25 @@ func newDSN(url string) (*dsn, error) {
 }
 
 func (d *dsn) AuthHeader() string {
+	return d.authHeader(4)
+}
+
+// EnvelopeAuthHeader returns the auth header used when submitting
+// events to the envelope endpoint, which requires protocol version 7.
+func (d *dsn) EnvelopeAuthHeader() string {
+	return d.authHeader(7)
+}
+
+func (d *dsn) authHeader(version int) string {
 	if d.PublicKey == "" {
 		return ""
 	}
 
 	if d.PrivateKey == "" {
-		return fmt.Sprintf("Sentry sentry_version=4, sentry_key=%s", d.PublicKey)
+		return fmt.Sprintf("Sentry sentry_version=%d, sentry_key=%s", version, d.PublicKey)
 	}
 
-	return fmt.Sprintf("Sentry sentry_version=4, sentry_key=%s, sentry_secret=%s", d.PublicKey, d.PrivateKey)
+	return fmt.Sprintf("Sentry sentry_version=%d, sentry_key=%s, sentry_secret=%s", version, d.PublicKey, d.PrivateKey)
 }
 
 func (d *dsn) Parse(dsn string) error {
@@ -102,16 +113,21 @@ func (d *dsn) Parse(dsn string) error {
 
 	uri.User = nil
 
+	basePath := ""
 	if idx := strings.LastIndex(uri.Path, "/"); idx != -1 {
 		d.ProjectID = uri.Path[idx+1:]
-		uri.Path = fmt.Sprintf("%s/", path.Join(uri.Path[:idx+1], "api", d.ProjectID, "store"))
+		basePath = uri.Path[:idx+1]
 	}
 
 	if d.ProjectID == "" {
 		return errors.Wrap(fmt.Errorf("missing Project ID"), ErrMissingProjectID.Error())
 	}
 
+	uri.Path = fmt.Sprintf("%s/", path.Join(basePath, "api", d.ProjectID, "store"))
 	d.URL = uri.String()
 
+	uri.Path = fmt.Sprintf("%s/", path.Join(basePath, "api", d.ProjectID, "envelope"))
+	d.EnvelopeURL = uri.String()
+
 	return nil
 }
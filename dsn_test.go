@@ -54,4 +54,17 @@ func TestDSN(t *testing.T) {
 		assert.Equal(t, "Sentry sentry_version=4, sentry_key=key", (&dsn{PublicKey: "key"}).AuthHeader(), "should return an auth header with just the public key if no private key is provided")
 		assert.Equal(t, "Sentry sentry_version=4, sentry_key=key, sentry_secret=secret", (&dsn{PublicKey: "key", PrivateKey: "secret"}).AuthHeader(), "should return a full auth header both the public and private key are provided")
 	})
+
+	t.Run("EnvelopeAuthHeader()", func(t *testing.T) {
+		assert.Equal(t, "", (&dsn{PrivateKey: "secret"}).EnvelopeAuthHeader(), "should return no auth header if no public key is provided")
+		assert.Equal(t, "Sentry sentry_version=7, sentry_key=key", (&dsn{PublicKey: "key"}).EnvelopeAuthHeader(), "should return an auth header with just the public key if no private key is provided")
+		assert.Equal(t, "Sentry sentry_version=7, sentry_key=key, sentry_secret=secret", (&dsn{PublicKey: "key", PrivateKey: "secret"}).EnvelopeAuthHeader(), "should return a full auth header both the public and private key are provided")
+	})
+
+	t.Run("URLs", func(t *testing.T) {
+		d, err := newDSN("https://u:p@example.com/sentry/1")
+		assert.Nil(t, err, "it should not return an error")
+		assert.Equal(t, "https://example.com/sentry/api/1/store/", d.URL, "it should use the store endpoint for the legacy URL")
+		assert.Equal(t, "https://example.com/sentry/api/1/envelope/", d.EnvelopeURL, "it should use the envelope endpoint for the envelope URL")
+	})
 }
@@ -0,0 +1,153 @@
+package sentry
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NewEnvelopeTransport creates a new Transport which submits events to
+// Sentry's `/api/{project}/envelope/` endpoint using the newline-delimited
+// envelope format. It is supported by newer Sentry servers and Relay
+// deployments and can be selected using the UseTransport() option.
+func NewEnvelopeTransport() Transport {
+	return &envelopeTransport{
+		client: newHTTPClient(),
+	}
+}
+
+type envelopeTransport struct {
+	client *http.Client
+	limits rateLimits
+}
+
+type envelopeHeader struct {
+	EventID string `json:"event_id,omitempty"`
+	SentAt  string `json:"sent_at"`
+}
+
+type envelopeItemHeader struct {
+	Type        string `json:"type"`
+	Length      int    `json:"length"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+func (t *envelopeTransport) Send(dsn string, packet Packet) error {
+	if dsn == "" {
+		return nil
+	}
+
+	if until, limited := t.limits.Until(dsn, rateLimitCategoryError); limited {
+		return errors.Wrap(&rateLimitWindowError{until}, ErrRateLimited.Error())
+	}
+
+	url, authHeader, err := t.parseDSN(dsn)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse DSN")
+	}
+
+	body, contentEncoding, err := t.serializePacket(packet)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize packet")
+	}
+
+	req, err := http.NewRequest("POST", url, body)
+	if err != nil {
+		return errors.Wrap(err, "failed to create new request")
+	}
+
+	req.Header.Set("X-Sentry-Auth", authHeader)
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("User-Agent", userAgent())
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to submit request")
+	}
+
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+
+	t.limits.Update(dsn, res)
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		return errors.Wrap(&httpStatusError{res.StatusCode}, ErrRateLimited.Error())
+	}
+
+	if res.StatusCode != 200 {
+		return &httpStatusError{res.StatusCode}
+	}
+
+	return nil
+}
+
+func (t *envelopeTransport) parseDSN(dsn string) (url, authHeader string, err error) {
+	d, err := newDSN(dsn)
+	if err != nil {
+		return "", "", err
+	}
+
+	return d.EnvelopeURL, d.EnvelopeAuthHeader(), nil
+}
+
+// serializePacket renders the packet as an envelope containing a single
+// event item. Large envelopes are gzip compressed, in which case the
+// returned content encoding will be non-empty.
+func (t *envelopeTransport) serializePacket(p Packet) (io.Reader, string, error) {
+	event, err := json.Marshal(p)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to encode JSON payload data")
+	}
+
+	header := envelopeHeader{
+		SentAt: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+
+	if pp, ok := p.(*packet); ok {
+		header.EventID = pp.getEventID()
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	enc := json.NewEncoder(buf)
+	if err := enc.Encode(header); err != nil {
+		return nil, "", errors.Wrap(err, "failed to encode envelope header")
+	}
+
+	if err := enc.Encode(envelopeItemHeader{
+		Type:        "event",
+		Length:      len(event),
+		ContentType: "application/json",
+	}); err != nil {
+		return nil, "", errors.Wrap(err, "failed to encode envelope item header")
+	}
+
+	buf.Write(event)
+	buf.WriteString("\n")
+
+	if buf.Len() < 1000 {
+		return buf, "", nil
+	}
+
+	cbuf := bytes.NewBuffer([]byte{})
+	gz, err := gzip.NewWriterLevel(cbuf, gzip.BestCompression)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to configure gzip compression")
+	}
+
+	if _, err := io.Copy(gz, buf); err != nil {
+		return nil, "", errors.Wrap(err, "failed to compress envelope")
+	}
+
+	gz.Close()
+
+	return cbuf, "gzip", nil
+}
@@ -1,6 +1,7 @@
 package sentry
 
 import (
+	"encoding/json"
 	"reflect"
 	"regexp"
 )
@@ -28,13 +29,110 @@ type ExceptionInfo struct {
 	ThreadID   string           `json:"thread_id,omitempty"`
 	Mechanism  string           `json:"mechanism,omitempty"`
 	StackTrace StackTraceOption `json:"stacktrace,omitempty"`
+
+	// MechanismInfo describes how the exception was captured. When it is
+	// set, it is sent in place of the Mechanism field.
+	MechanismInfo *ExceptionMechanism `json:"-"`
+}
+
+// MarshalJSON serializes the exception, using its MechanismInfo as the
+// mechanism if it has been provided.
+func (e ExceptionInfo) MarshalJSON() ([]byte, error) {
+	type exceptionInfo ExceptionInfo
+
+	data := struct {
+		*exceptionInfo
+		Mechanism interface{} `json:"mechanism,omitempty"`
+	}{
+		exceptionInfo: (*exceptionInfo)(&e),
+	}
+
+	if e.MechanismInfo != nil {
+		data.Mechanism = e.MechanismInfo
+	} else if e.Mechanism != "" {
+		data.Mechanism = e.Mechanism
+	}
+
+	return json.Marshal(data)
+}
+
+// clone creates a copy of this exception, including its mechanism and any
+// stacktrace created by this package, which will not be affected by any
+// later changes to it.
+func (e *ExceptionInfo) clone() ExceptionInfo {
+	c := *e
+
+	if e.MechanismInfo != nil {
+		c.MechanismInfo = e.MechanismInfo.clone()
+	}
+
+	if st, ok := e.StackTrace.(*stackTraceOption); ok {
+		c.StackTrace = st.clone()
+	}
+
+	return c
+}
+
+// An ExceptionMechanism describes how an exception was captured, for
+// example by a panic handler, and whether it was handled by your
+// application or not.
+type ExceptionMechanism struct {
+	Type        string                 `json:"type"`
+	Description string                 `json:"description,omitempty"`
+	HelpLink    string                 `json:"help_link,omitempty"`
+	Handled     *bool                  `json:"handled,omitempty"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+
+	// These fields describe how exceptions in an exception group relate
+	// to one another, and are set by ExceptionForError.
+	ExceptionID      *int `json:"exception_id,omitempty"`
+	ParentID         *int `json:"parent_id,omitempty"`
+	IsExceptionGroup bool `json:"is_exception_group,omitempty"`
+}
+
+// NewExceptionMechanism creates a new ExceptionMechanism of the given
+// type, recording whether the exception was handled by your application.
+func NewExceptionMechanism(mechanismType string, handled bool) *ExceptionMechanism {
+	return &ExceptionMechanism{
+		Type:    mechanismType,
+		Handled: &handled,
+	}
+}
+
+// clone creates a copy of this mechanism which will not be affected by any
+// later changes to it.
+func (m *ExceptionMechanism) clone() *ExceptionMechanism {
+	c := *m
+
+	if m.Handled != nil {
+		handled := *m.Handled
+		c.Handled = &handled
+	}
+
+	if m.ExceptionID != nil {
+		id := *m.ExceptionID
+		c.ExceptionID = &id
+	}
+
+	if m.ParentID != nil {
+		id := *m.ParentID
+		c.ParentID = &id
+	}
+
+	if m.Data != nil {
+		c.Data = make(map[string]interface{}, len(m.Data))
+		for k, v := range m.Data {
+			c.Data[k] = v
+		}
+	}
+
+	return &c
 }
 
 // ForError updates an ExceptionInfo object with information sourced
 // from an error.
 func (e *ExceptionInfo) ForError(err error) *ExceptionInfo {
-	e.Type = reflect.TypeOf(err).String()
-	e.Value = err.Error()
+	e.describeError(err)
 
 	if e.StackTrace == nil {
 		e.StackTrace = StackTrace().ForError(err)
@@ -42,45 +140,146 @@ func (e *ExceptionInfo) ForError(err error) *ExceptionInfo {
 		e.StackTrace.ForError(err)
 	}
 
+	return e
+}
+
+// describeError sets the type, value and module of the exception from an
+// error, without changing its stack trace.
+func (e *ExceptionInfo) describeError(err error) {
+	e.Type = reflect.TypeOf(err).String()
+	e.Value = err.Error()
+
 	if m := errorMsgPattern.FindStringSubmatch(err.Error()); m != nil {
 		e.Module = m[1]
 		e.Type = m[2]
 	}
-
-	return e
 }
 
 // ExceptionForError allows you to include the details of an error which
 // occurred within your application as part of the event you send to Sentry.
+//
+// Each error in the chain of causes will be included as its own exception.
+// Errors which record where they were created, like those from pkg/errors,
+// will include that stack trace, while those which do not will be sent
+// without one. If no error in the chain has a stack trace, the location
+// that ExceptionForError was called from is used for the outermost error.
+//
+// Errors which wrap several others, like those created by errors.Join or
+// which implement WrappedErrors() []error, are sent as exception groups so
+// that every branch of the tree is included in the event.
 func ExceptionForError(err error) Option {
 	if err == nil {
 		return nil
 	}
 
 	exceptions := []*ExceptionInfo{}
+	hasStackTrace := false
+	hasGroups := false
+
+	// The errors are visited outermost first, with each being given an ID
+	// (its index) which the errors it wraps refer to as their parent.
+	var visit func(err error, parentID *int)
+	visit = func(err error, parentID *int) {
+		for err != nil && len(exceptions) < maxExceptionsForError {
+			id := len(exceptions)
+
+			ex := &ExceptionInfo{
+				MechanismInfo: &ExceptionMechanism{
+					Type:        "chained",
+					ExceptionID: &id,
+					ParentID:    parentID,
+				},
+			}
+			ex.describeError(err)
+
+			if _, ok := err.(stackTracer); ok {
+				ex.StackTrace = stackTraceForError(err)
+				hasStackTrace = true
+			}
+
+			exceptions = append(exceptions, ex)
+
+			if errs, ok := unwrapErrorGroup(err); ok {
+				ex.MechanismInfo.IsExceptionGroup = true
+				hasGroups = true
+
+				for _, err := range errs {
+					visit(err, &id)
+				}
+
+				return
+			}
 
-	for err != nil {
-		exceptions = append([]*ExceptionInfo{NewExceptionInfo().ForError(err)}, exceptions...)
-
-		switch e := err.(type) {
-		case interface {
-			Cause() error
-		}:
-			err = e.Cause()
-		case interface {
-			Unwrap() error
-		}:
-			err = e.Unwrap()
-		default:
-			err = nil
+			err = unwrapError(err)
+			parentID = &id
 		}
 	}
 
+	visit(err, nil)
+
+	if hasGroups {
+		exceptions[0].MechanismInfo.Type = "generic"
+	} else {
+		// Sentry can represent a simple chain of errors without needing
+		// to know how they relate to one another.
+		for _, ex := range exceptions {
+			ex.MechanismInfo = nil
+		}
+	}
+
+	if !hasStackTrace {
+		exceptions[0].StackTrace = StackTrace()
+	}
+
+	// Sentry expects the outermost exception to be last.
+	for i, j := 0, len(exceptions)-1; i < j; i, j = i+1, j-1 {
+		exceptions[i], exceptions[j] = exceptions[j], exceptions[i]
+	}
+
 	return &exceptionOption{
 		Exceptions: exceptions,
 	}
 }
 
+// maxExceptionsForError limits the number of exceptions which will be sent
+// for a single error, protecting against very large (or cyclic) trees.
+const maxExceptionsForError = 100
+
+// unwrapError retrieves the error which is wrapped by another, supporting
+// both pkg/errors' Cause() and the standard library's Unwrap().
+func unwrapError(err error) error {
+	switch e := err.(type) {
+	case interface {
+		Cause() error
+	}:
+		return e.Cause()
+	case interface {
+		Unwrap() error
+	}:
+		return e.Unwrap()
+	default:
+		return nil
+	}
+}
+
+// unwrapErrorGroup retrieves the errors which are wrapped by an error that
+// combines several others, like those created by errors.Join or
+// github.com/hashicorp/go-multierror.
+func unwrapErrorGroup(err error) ([]error, bool) {
+	switch e := err.(type) {
+	case interface {
+		Unwrap() []error
+	}:
+		return e.Unwrap(), true
+	case interface {
+		WrappedErrors() []error
+	}:
+		return e.WrappedErrors(), true
+	default:
+		return nil, false
+	}
+}
+
 // Exception allows you to include the details of an exception which occurred
 // within your application as part of the event you send to Sentry.
 func Exception(info *ExceptionInfo) Option {
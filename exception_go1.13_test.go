@@ -6,6 +6,7 @@ import (
 	"testing"
 	"fmt"
 	
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -24,5 +25,24 @@ func TestExceptionForErrorWrappingGo113(t *testing.T) {
 
 		assert.Len(t, exx.Exceptions, 4)
 		assert.Equal(t, "root cause", exx.Exceptions[0].Value)
+
+		for i, ex := range exx.Exceptions[:3] {
+			assert.Nil(t, ex.StackTrace, "wrapped errors without their own stack should not include a stacktrace (index=%d)", i)
+		}
+		assert.NotNil(t, exx.Exceptions[3].StackTrace, "the outermost error should use the location of the current call as its stacktrace")
+	})
+
+	t.Run("Mixed", func(t *testing.T) {
+		err := fmt.Errorf("example error: %w", errors.Wrap(errors.New("root cause"), "cause 1"))
+
+		exx, ok := ExceptionForError(err).(*exceptionOption)
+		assert.True(t, ok, "the option should actually be a *exceptionOption")
+
+		if assert.Len(t, exx.Exceptions, 4) {
+			assert.NotNil(t, exx.Exceptions[0].StackTrace, "the root cause should include its stacktrace")
+			assert.Nil(t, exx.Exceptions[1].StackTrace, "the message wrapper should not include a stacktrace")
+			assert.NotNil(t, exx.Exceptions[2].StackTrace, "the stack wrapper should include its stacktrace")
+			assert.Nil(t, exx.Exceptions[3].StackTrace, "the outermost error should not duplicate the location of the current call")
+		}
 	})
 }
\ No newline at end of file
@@ -0,0 +1,44 @@
+//go:build go1.20
+// +build go1.20
+
+package sentry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExceptionForErrorJoinGo120(t *testing.T) {
+	t.Run("errors.Join()", func(t *testing.T) {
+		err := errors.Join(fmt.Errorf("first"), fmt.Errorf("second: %w", fmt.Errorf("cause")))
+
+		exx, ok := ExceptionForError(err).(*exceptionOption)
+		require.True(t, ok, "the option should actually be a *exceptionOption")
+		require.Len(t, exx.Exceptions, 4, "every branch of the tree should be included")
+
+		group := exx.Exceptions[3]
+		assert.Equal(t, "first\nsecond: cause", group.Value, "the outermost exception should be the joined error")
+		if assert.NotNil(t, group.MechanismInfo, "the group should include a mechanism") {
+			assert.True(t, group.MechanismInfo.IsExceptionGroup, "the joined error should be marked as an exception group")
+			assert.Equal(t, 0, *group.MechanismInfo.ExceptionID, "the joined error should be the root of the tree")
+			assert.Nil(t, group.MechanismInfo.ParentID, "the joined error should not have a parent")
+		}
+
+		parents := map[string]int{}
+		for _, ex := range exx.Exceptions[:3] {
+			if assert.NotNil(t, ex.MechanismInfo, "every exception should include a mechanism") && assert.NotNil(t, ex.MechanismInfo.ParentID, "every exception should have a parent") {
+				parents[ex.Value] = *ex.MechanismInfo.ParentID
+			}
+		}
+
+		assert.Equal(t, map[string]int{
+			"first":         0,
+			"second: cause": 0,
+			"cause":         2,
+		}, parents, "each exception should refer to the error which wrapped it")
+	})
+}
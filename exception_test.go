@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestException(t *testing.T) {
@@ -92,6 +93,44 @@ func TestException(t *testing.T) {
 			},
 		}, serialized)
 	})
+
+	t.Run("MarshalJSON() with a mechanism", func(t *testing.T) {
+		serialized := testOptionsSerialize(t, Exception(&ExceptionInfo{
+			Type:      "TestException",
+			Value:     "This is a test",
+			Mechanism: "generic",
+		}))
+
+		assert.Equal(t, map[string]interface{}{
+			"values": []interface{}{
+				map[string]interface{}{
+					"type":      "TestException",
+					"value":     "This is a test",
+					"mechanism": "generic",
+				},
+			},
+		}, serialized, "it should serialize the mechanism string")
+
+		serialized = testOptionsSerialize(t, Exception(&ExceptionInfo{
+			Type:          "TestException",
+			Value:         "This is a test",
+			Mechanism:     "generic",
+			MechanismInfo: NewExceptionMechanism("panic", false),
+		}))
+
+		assert.Equal(t, map[string]interface{}{
+			"values": []interface{}{
+				map[string]interface{}{
+					"type":  "TestException",
+					"value": "This is a test",
+					"mechanism": map[string]interface{}{
+						"type":    "panic",
+						"handled": false,
+					},
+				},
+			},
+		}, serialized, "it should prefer the mechanism info if it is provided")
+	})
 }
 
 func TestExceptionForError(t *testing.T) {
@@ -119,6 +158,158 @@ func TestExceptionForError(t *testing.T) {
 		// 2 - withStack{}
 		assert.Len(t, exx.Exceptions, 1 + (3*2))
 		assert.Equal(t, "root cause", exx.Exceptions[0].Value)
+
+		t.Run("StackTraces", func(t *testing.T) {
+			topFrames := map[int]bool{}
+
+			for i, ex := range exx.Exceptions {
+				// Exceptions alternate between those with their own stacks
+				// (the root cause and withStack{}) and those without (withMessage{}).
+				if i%2 == 1 {
+					assert.Nil(t, ex.StackTrace, "exceptions without their own stack should not include a stacktrace (index=%d)", i)
+					continue
+				}
+
+				sti, ok := ex.StackTrace.(*stackTraceOption)
+				if assert.True(t, ok, "exceptions with their own stack should include a stacktrace (index=%d)", i) && assert.NotEmpty(t, sti.Frames, "the stacktrace should include frames (index=%d)", i) {
+					topFrames[sti.Frames[len(sti.Frames)-1].Line] = true
+				}
+			}
+
+			assert.Len(t, topFrames, 4, "each level should use the stacktrace from where it was created")
+		})
+	})
+
+	t.Run("fmt.Errorf()", func(t *testing.T) {
+		exx := ExceptionForError(fmt.Errorf("example error")).(*exceptionOption)
+		if assert.Len(t, exx.Exceptions, 1, "there should be a single exception") {
+			sti, ok := exx.Exceptions[0].StackTrace.(*stackTraceOption)
+			if assert.True(t, ok, "it should fall back to the location of the current call") {
+				assert.NotEmpty(t, sti.Frames, "the stacktrace should include frames")
+			}
+		}
+	})
+}
+
+type testMultiError struct {
+	errs []error
+}
+
+func (e *testMultiError) Error() string {
+	return fmt.Sprintf("%d errors occurred", len(e.errs))
+}
+
+func (e *testMultiError) Unwrap() []error {
+	return e.errs
+}
+
+type testWrappedErrors struct {
+	errs []error
+}
+
+func (e *testWrappedErrors) Error() string {
+	return "wrapped errors"
+}
+
+func (e *testWrappedErrors) WrappedErrors() []error {
+	return e.errs
+}
+
+func TestExceptionForErrorGroups(t *testing.T) {
+	intPtr := func(i int) *int {
+		return &i
+	}
+
+	t.Run("Simple Chains", func(t *testing.T) {
+		exx := ExceptionForError(errors.Wrap(errors.New("root cause"), "example error")).(*exceptionOption)
+		for i, ex := range exx.Exceptions {
+			assert.Nil(t, ex.MechanismInfo, "simple chains should not include a mechanism (index=%d)", i)
+		}
+	})
+
+	t.Run("Unwrap() []error", func(t *testing.T) {
+		err := fmt.Errorf("example error: %w", &testMultiError{[]error{
+			fmt.Errorf("first"),
+			nil,
+			&testWrappedErrors{[]error{fmt.Errorf("second")}},
+		}})
+
+		exx := ExceptionForError(err).(*exceptionOption)
+		require.Len(t, exx.Exceptions, 5, "every branch of the tree should be included")
+
+		values := []string{}
+		for _, ex := range exx.Exceptions {
+			values = append(values, ex.Value)
+		}
+		assert.Equal(t, []string{"second", "wrapped errors", "first", "3 errors occurred", "example error: 3 errors occurred"}, values, "the outermost exception should be last")
+
+		mechanisms := map[string]*ExceptionMechanism{}
+		for _, ex := range exx.Exceptions {
+			require.NotNil(t, ex.MechanismInfo, "every exception should include a mechanism")
+			mechanisms[ex.Value] = ex.MechanismInfo
+		}
+
+		assert.Equal(t, &ExceptionMechanism{Type: "generic", ExceptionID: intPtr(0)}, mechanisms["example error: 3 errors occurred"], "the outermost exception should be the root of the tree")
+		assert.Equal(t, &ExceptionMechanism{Type: "chained", ExceptionID: intPtr(1), ParentID: intPtr(0), IsExceptionGroup: true}, mechanisms["3 errors occurred"], "it should mark errors which implement Unwrap() []error as groups")
+		assert.Equal(t, &ExceptionMechanism{Type: "chained", ExceptionID: intPtr(2), ParentID: intPtr(1)}, mechanisms["first"], "it should include the group's first member")
+		assert.Equal(t, &ExceptionMechanism{Type: "chained", ExceptionID: intPtr(3), ParentID: intPtr(1), IsExceptionGroup: true}, mechanisms["wrapped errors"], "it should mark errors which implement WrappedErrors() as groups")
+		assert.Equal(t, &ExceptionMechanism{Type: "chained", ExceptionID: intPtr(4), ParentID: intPtr(3)}, mechanisms["second"], "it should include nested groups")
+
+		assert.NotNil(t, exx.Exceptions[4].StackTrace, "the outermost error should use the location of the current call as its stacktrace")
+		for i, ex := range exx.Exceptions[:4] {
+			assert.Nil(t, ex.StackTrace, "errors without their own stack should not include a stacktrace (index=%d)", i)
+		}
+	})
+
+	t.Run("Stack Traces", func(t *testing.T) {
+		exx := ExceptionForError(&testMultiError{[]error{errors.New("first"), fmt.Errorf("second")}}).(*exceptionOption)
+		require.Len(t, exx.Exceptions, 3, "every branch of the tree should be included")
+
+		assert.Nil(t, exx.Exceptions[0].StackTrace, "errors without their own stack should not include a stacktrace")
+		assert.NotNil(t, exx.Exceptions[1].StackTrace, "errors with their own stack should include it")
+		assert.Nil(t, exx.Exceptions[2].StackTrace, "the outermost error should not duplicate the location of the current call")
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		errs := make([]error, maxExceptionsForError*2)
+		for i := range errs {
+			errs[i] = fmt.Errorf("error %d", i)
+		}
+
+		exx := ExceptionForError(&testMultiError{errs}).(*exceptionOption)
+		assert.Len(t, exx.Exceptions, maxExceptionsForError, "it should limit the number of exceptions")
+	})
+
+	t.Run("MarshalJSON()", func(t *testing.T) {
+		serialized := testOptionsSerialize(t, &exceptionOption{
+			Exceptions: []*ExceptionInfo{
+				{
+					Type:  "TestException",
+					Value: "This is a test",
+					MechanismInfo: &ExceptionMechanism{
+						Type:             "chained",
+						ExceptionID:      intPtr(1),
+						ParentID:         intPtr(0),
+						IsExceptionGroup: true,
+					},
+				},
+			},
+		})
+
+		assert.Equal(t, map[string]interface{}{
+			"values": []interface{}{
+				map[string]interface{}{
+					"type":  "TestException",
+					"value": "This is a test",
+					"mechanism": map[string]interface{}{
+						"type":               "chained",
+						"exception_id":       float64(1),
+						"parent_id":          float64(0),
+						"is_exception_group": true,
+					},
+				},
+			},
+		}, serialized)
 	})
 }
 
@@ -0,0 +1,166 @@
+package sentry
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// An HTTPHandler wraps a net/http handler and reports any panics which
+// occur while serving requests to Sentry, along with the details of the
+// request which caused them.
+type HTTPHandler interface {
+	http.Handler
+
+	// WithServerErrors will cause the handler to report any responses
+	// with a status code of 500 or above to Sentry.
+	WithServerErrors() HTTPHandler
+
+	// WithRequestOptions allows you to control which details of each
+	// request are sent to Sentry, for example by including its headers.
+	WithRequestOptions(configure func(opt HTTPRequestOption) HTTPRequestOption) HTTPHandler
+}
+
+// NewHTTPHandler creates a new HTTPHandler which wraps the provided handler.
+// Each request is served with its own client, derived from the one you
+// provide and including the request's details, which can be retrieved
+// by your handlers using ClientFromRequest().
+//
+// Panics are recovered and reported in the same way as
+// RecoveringClient.Recover(), honouring the client's RecoverTimeout and
+// RecoverRepanic options. If the panic is not re-raised, a 500 Internal
+// Server Error will be returned to the caller if nothing has been written
+// yet.
+func NewHTTPHandler(cl Client, handler http.Handler) HTTPHandler {
+	if cl == nil {
+		cl = DefaultClient()
+	}
+
+	return &httpHandler{
+		client:  cl,
+		handler: handler,
+	}
+}
+
+// ClientFromRequest retrieves the client associated with a request by an
+// HTTPHandler. It is equivalent to calling ClientFromContext() with the
+// request's context.
+func ClientFromRequest(req *http.Request) Client {
+	return ClientFromContext(req.Context())
+}
+
+type httpHandler struct {
+	client         Client
+	handler        http.Handler
+	serverErrors   bool
+	requestOptions func(opt HTTPRequestOption) HTTPRequestOption
+}
+
+func (h *httpHandler) WithServerErrors() HTTPHandler {
+	h.serverErrors = true
+	return h
+}
+
+func (h *httpHandler) WithRequestOptions(configure func(opt HTTPRequestOption) HTTPRequestOption) HTTPHandler {
+	h.requestOptions = configure
+	return h
+}
+
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	started := time.Now()
+
+	reqOpt := HTTPRequest(req)
+	if h.requestOptions != nil {
+		reqOpt = h.requestOptions(reqOpt)
+	}
+
+	cl := h.client.With(reqOpt)
+	res := &httpResponseRecorder{ResponseWriter: w}
+	req = req.WithContext(ContextWithClient(req.Context(), cl))
+
+	defer func() {
+		duration := Extra(map[string]interface{}{
+			"duration": time.Since(started).String(),
+		})
+
+		if r := recover(); r != nil {
+			if r == http.ErrAbortHandler {
+				// This is used to intentionally abort a response and
+				// should not be reported.
+				panic(r)
+			}
+
+			waitForPanic(cl, capturePanic(cl, r, duration))
+
+			if shouldRepanic(cl) {
+				panic(r)
+			}
+
+			if !res.wroteHeader {
+				http.Error(res, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		if h.serverErrors && res.Status() >= 500 {
+			cl.Capture(
+				Message("%s %s responded with %d %s", req.Method, req.URL.Path, res.Status(), http.StatusText(res.Status())),
+				duration,
+			)
+		}
+	}()
+
+	h.handler.ServeHTTP(res, req)
+}
+
+// httpResponseRecorder keeps track of the status code which was sent in
+// response to a request.
+type httpResponseRecorder struct {
+	http.ResponseWriter
+
+	status      int
+	wroteHeader bool
+}
+
+func (r *httpResponseRecorder) Status() int {
+	if !r.wroteHeader {
+		return http.StatusOK
+	}
+
+	return r.status
+}
+
+func (r *httpResponseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *httpResponseRecorder) Write(data []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *httpResponseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *httpResponseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := r.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, errors.New("sentry: the response writer does not support hijacking")
+}
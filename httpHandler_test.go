@@ -0,0 +1,227 @@
+package sentry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewHTTPHandler() {
+	cl := NewClient(
+		Release("v1.0.0"),
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		// You can retrieve a client which includes the request's details
+		ClientFromRequest(req).Capture(
+			Message("Route Not Found: [%s] %s", req.Method, req.URL.Path),
+			Level(Warning),
+		)
+
+		http.NotFound(res, req)
+	})
+
+	handler := NewHTTPHandler(cl, mux).
+		// Report any responses with a 5xx status code
+		WithServerErrors().
+		// And include the request's headers in your events
+		WithRequestOptions(func(opt HTTPRequestOption) HTTPRequestOption {
+			return opt.WithHeaders()
+		})
+
+	http.ListenAndServe(":8080", handler)
+}
+
+func TestHTTPHandler(t *testing.T) {
+	tr := &testFlakyTransport{}
+	q := NewSequentialSendQueue(10)
+	defer q.Shutdown(true)
+
+	cl := NewClient(UseTransport(tr), UseSendQueue(q))
+
+	sent := func(t *testing.T) []Packet {
+		assert.Nil(t, q.(FlushableSendQueue).Flush(context.Background()), "the queue should be flushed")
+
+		tr.mutex.Lock()
+		defer tr.mutex.Unlock()
+
+		packets := tr.sent
+		tr.sent = nil
+		return packets
+	}
+
+	serve := func(h http.Handler) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "https://example.com/test?q=1", nil)
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		return res
+	}
+
+	requestOf := func(t *testing.T, p Packet) *http.Request {
+		opt, ok := p.Get("request").(*httpRequestOption)
+		require.True(t, ok, "the packet should include the request")
+		return opt.request
+	}
+
+	h := NewHTTPHandler(cl, http.NotFoundHandler())
+	require.NotNil(t, h, "it should not return nil")
+	assert.Implements(t, (*http.Handler)(nil), h, "it should implement the http.Handler interface")
+
+	hh, ok := h.(*httpHandler)
+	require.True(t, ok, "it should actually be a *httpHandler")
+
+	t.Run("NewHTTPHandler()", func(t *testing.T) {
+		h := NewHTTPHandler(nil, http.NotFoundHandler()).(*httpHandler)
+		assert.Equal(t, DefaultClient(), h.client, "it should use the default client if none is provided")
+	})
+
+	t.Run("WithServerErrors()", func(t *testing.T) {
+		assert.Equal(t, h, h.WithServerErrors(), "it should return the handler for chaining")
+		assert.True(t, hh.serverErrors, "it should enable reporting of server errors")
+		hh.serverErrors = false
+	})
+
+	t.Run("WithRequestOptions()", func(t *testing.T) {
+		configure := func(opt HTTPRequestOption) HTTPRequestOption {
+			return opt.WithHeaders()
+		}
+
+		assert.Equal(t, h, h.WithRequestOptions(configure), "it should return the handler for chaining")
+		assert.NotNil(t, hh.requestOptions, "it should set the request options")
+		hh.requestOptions = nil
+	})
+
+	t.Run("ServeHTTP()", func(t *testing.T) {
+		t.Run("ClientFromRequest()", func(t *testing.T) {
+			req := httptest.NewRequest("GET", "https://example.com/test", nil)
+			assert.Equal(t, DefaultClient(), ClientFromRequest(req), "it should return the default client if the request was not served by an HTTPHandler")
+
+			res := serve(NewHTTPHandler(cl, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				ClientFromRequest(req).Capture(Message("test"))
+				res.WriteHeader(http.StatusNoContent)
+			})))
+
+			assert.Equal(t, http.StatusNoContent, res.Code, "the handler should be called")
+
+			packets := sent(t)
+			require.Len(t, packets, 1, "the event should have been sent")
+			assert.Equal(t, "/test", requestOf(t, packets[0]).URL.Path, "the event should include the request")
+		})
+
+		t.Run("WithRequestOptions()", func(t *testing.T) {
+			serve(NewHTTPHandler(cl, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				ClientFromRequest(req).Capture(Message("test"))
+			})).WithRequestOptions(func(opt HTTPRequestOption) HTTPRequestOption {
+				return opt.WithHeaders()
+			}))
+
+			packets := sent(t)
+			require.Len(t, packets, 1, "the event should have been sent")
+
+			opt, ok := packets[0].Get("request").(*httpRequestOption)
+			require.True(t, ok, "the packet should include the request")
+			assert.True(t, opt.withHeaders, "the request options should have been configured")
+		})
+
+		t.Run("Panic", func(t *testing.T) {
+			res := serve(NewHTTPHandler(cl, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				panic("test panic")
+			})))
+
+			assert.Equal(t, http.StatusInternalServerError, res.Code, "it should respond with a 500 status code")
+
+			packets := sent(t)
+			require.Len(t, packets, 1, "the panic should have been reported")
+			assert.Equal(t, "/test", requestOf(t, packets[0]).URL.Path, "the event should include the request")
+
+			exceptions := packets[0].Exceptions()
+			require.Len(t, exceptions, 1, "the event should include an exception")
+			assert.Equal(t, "test panic", exceptions[0].Value, "the exception should describe the panic")
+			if assert.NotNil(t, exceptions[0].MechanismInfo, "the exception should include the mechanism") {
+				assert.Equal(t, "panic", exceptions[0].MechanismInfo.Type, "the exception should use the panic mechanism")
+			}
+
+			extra, ok := packets[0].Get("extra").(*extraOption)
+			if assert.True(t, ok, "the event should include extra information") {
+				assert.Contains(t, extra.extra, "duration", "the event should include the request duration")
+			}
+		})
+
+		t.Run("Panic after Response", func(t *testing.T) {
+			res := serve(NewHTTPHandler(cl, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.WriteHeader(http.StatusAccepted)
+				panic("test panic")
+			})))
+
+			assert.Equal(t, http.StatusAccepted, res.Code, "it should not modify a response which has already been sent")
+			assert.Len(t, sent(t), 1, "the panic should have been reported")
+		})
+
+		t.Run("Repanic", func(t *testing.T) {
+			h := NewHTTPHandler(cl.With(RecoverRepanic(true)), http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				panic("test panic")
+			}))
+
+			assert.PanicsWithValue(t, "test panic", func() {
+				serve(h)
+			}, "it should re-panic if the client is configured to")
+			assert.Len(t, sent(t), 1, "the panic should have been reported")
+		})
+
+		t.Run("ErrAbortHandler", func(t *testing.T) {
+			h := NewHTTPHandler(cl, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				panic(http.ErrAbortHandler)
+			}))
+
+			assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+				serve(h)
+			}, "it should re-panic with http.ErrAbortHandler")
+			assert.Empty(t, sent(t), "aborted requests should not be reported")
+		})
+
+		t.Run("Server Errors", func(t *testing.T) {
+			failing := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.WriteHeader(http.StatusBadGateway)
+			})
+
+			res := serve(NewHTTPHandler(cl, failing))
+			assert.Equal(t, http.StatusBadGateway, res.Code, "it should pass the response through")
+			assert.Empty(t, sent(t), "server errors should not be reported by default")
+
+			serve(NewHTTPHandler(cl, failing).WithServerErrors())
+			packets := sent(t)
+			require.Len(t, packets, 1, "server errors should be reported when enabled")
+			assert.Equal(t, "GET /test responded with 502 Bad Gateway", packets[0].Message(), "the event should describe the response")
+
+			serve(NewHTTPHandler(cl, http.NotFoundHandler()).WithServerErrors())
+			assert.Empty(t, sent(t), "client errors should not be reported")
+
+			serve(NewHTTPHandler(cl, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.Write([]byte("OK"))
+			})).WithServerErrors())
+			assert.Empty(t, sent(t), "successful responses should not be reported")
+		})
+	})
+}
+
+func TestHTTPResponseRecorder(t *testing.T) {
+	res := httptest.NewRecorder()
+	r := &httpResponseRecorder{ResponseWriter: res}
+
+	assert.Equal(t, http.StatusOK, r.Status(), "it should report a 200 status if nothing has been written")
+
+	r.WriteHeader(http.StatusCreated)
+	r.WriteHeader(http.StatusInternalServerError)
+	assert.Equal(t, http.StatusCreated, r.Status(), "it should report the first status code which was written")
+
+	r.Flush()
+	assert.True(t, res.Flushed, "it should flush the underlying response writer")
+
+	_, _, err := r.Hijack()
+	assert.NotNil(t, err, "it should return an error if the underlying response writer cannot be hijacked")
+}
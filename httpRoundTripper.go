@@ -0,0 +1,111 @@
+package sentry
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// An HTTPRoundTripper wraps a net/http RoundTripper and records a
+// breadcrumb describing each outbound request it makes, including
+// requests which fail.
+type HTTPRoundTripper interface {
+	http.RoundTripper
+
+	// WithBreadcrumbs sets the list of breadcrumbs which requests will
+	// be recorded in. If you do not provide one, the breadcrumbs used by
+	// the client in the request's context will be used instead.
+	WithBreadcrumbs(list BreadcrumbsList) HTTPRoundTripper
+}
+
+// NewHTTPRoundTripper creates a new HTTPRoundTripper which wraps the
+// provided RoundTripper, or http.DefaultTransport if it is nil.
+//
+// Credentials, query strings and fragments are removed from the URLs
+// which are recorded, since they frequently contain sensitive values.
+// If you provide your own BreadcrumbsList implementation, requests will
+// be recorded using its NewHTTPRequest method and won't include their
+// duration.
+func NewHTTPRoundTripper(next http.RoundTripper) HTTPRoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &httpRoundTripper{
+		next: next,
+	}
+}
+
+type httpRoundTripper struct {
+	next        http.RoundTripper
+	breadcrumbs BreadcrumbsList
+}
+
+func (t *httpRoundTripper) WithBreadcrumbs(list BreadcrumbsList) HTTPRoundTripper {
+	t.breadcrumbs = list
+	return t
+}
+
+func (t *httpRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	started := time.Now()
+	res, err := t.next.RoundTrip(req)
+
+	list := t.breadcrumbsFor(req)
+	if list == nil {
+		return res, err
+	}
+
+	statusCode, reason := 0, ""
+	if err != nil {
+		reason = err.Error()
+	} else {
+		statusCode, reason = res.StatusCode, http.StatusText(res.StatusCode)
+	}
+
+	l, ok := list.(*breadcrumbsList)
+	if !ok {
+		b := list.NewHTTPRequest(req.Method, sanitizeURL(req.URL), statusCode, reason)
+		if err != nil {
+			b.WithLevel(Error)
+		}
+
+		return res, err
+	}
+
+	// The breadcrumb is completed before it is added to the list, since
+	// events may be reading the list's breadcrumbs at the same time.
+	b := newHTTPRequestBreadcrumb(req.Method, sanitizeURL(req.URL), statusCode, reason)
+	b.Data["duration_ms"] = float64(time.Since(started)) / float64(time.Millisecond)
+	if err != nil {
+		b.Level = Error
+	}
+
+	l.append(b)
+	return res, err
+}
+
+// breadcrumbsFor determines which list of breadcrumbs a request should be
+// recorded in.
+func (t *httpRoundTripper) breadcrumbsFor(req *http.Request) BreadcrumbsList {
+	if t.breadcrumbs != nil {
+		return t.breadcrumbs
+	}
+
+	list, _ := ClientFromContext(req.Context()).GetOption("breadcrumbs").(BreadcrumbsList)
+	return list
+}
+
+// sanitizeURL removes any credentials, query string and fragment from a URL
+// so that it can be safely included in a breadcrumb.
+func sanitizeURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	su := *u
+	su.User = nil
+	su.RawQuery = ""
+	su.ForceQuery = false
+	su.Fragment = ""
+	return su.String()
+}
@@ -24,28 +24,31 @@ const (
 
 type httpTransport struct {
 	client *http.Client
+	limits rateLimits
 }
 
 func newHTTPTransport() Transport {
-	t := &httpTransport{
-		client: http.DefaultClient,
+	return &httpTransport{
+		client: newHTTPClient(),
 	}
+}
 
+// newHTTPClient creates the HTTP client used by our transports to
+// communicate with Sentry, falling back on the default client if we
+// are unable to load the root TLS certificates.
+func newHTTPClient() *http.Client {
 	rootCAs, err := gocertifi.CACerts()
 	if err != nil {
-
 		log.Println(ErrMissingRootTLSCerts.Error())
-		return t
+		return http.DefaultClient
 	}
 
-	t.client = &http.Client{
+	return &http.Client{
 		Transport: &http.Transport{
 			Proxy:           http.ProxyFromEnvironment,
 			TLSClientConfig: &tls.Config{RootCAs: rootCAs},
 		},
 	}
-
-	return t
 }
 
 func (t *httpTransport) Send(dsn string, packet Packet) error {
@@ -53,6 +56,10 @@ func (t *httpTransport) Send(dsn string, packet Packet) error {
 		return nil
 	}
 
+	if until, limited := t.limits.Until(dsn, rateLimitCategoryError); limited {
+		return errors.Wrap(&rateLimitWindowError{until}, ErrRateLimited.Error())
+	}
+
 	url, authHeader, err := t.parseDSN(dsn)
 	if err != nil {
 		return errors.Wrap(err, "failed to parse DSN")
@@ -70,7 +77,7 @@ func (t *httpTransport) Send(dsn string, packet Packet) error {
 
 	req.Header.Set("X-Sentry-Auth", authHeader)
 	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("User-Agent", fmt.Sprintf("sentry-go %s (Sierra Softworks; github.com/SierraSoftworks/sentry-go)", version))
+	req.Header.Set("User-Agent", userAgent())
 
 	res, err := t.client.Do(req)
 	if err != nil {
@@ -80,13 +87,33 @@ func (t *httpTransport) Send(dsn string, packet Packet) error {
 	io.Copy(ioutil.Discard, res.Body)
 	res.Body.Close()
 
+	t.limits.Update(dsn, res)
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		return errors.Wrap(&httpStatusError{res.StatusCode}, ErrRateLimited.Error())
+	}
+
 	if res.StatusCode != 200 {
-		return errors.Errorf("got http status %d, expected 200", res.StatusCode)
+		return &httpStatusError{res.StatusCode}
 	}
 
 	return nil
 }
 
+// httpStatusError is returned by our transports when Sentry responds
+// with an unexpected HTTP status code.
+type httpStatusError struct {
+	StatusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("got http status %d, expected 200", e.StatusCode)
+}
+
+func userAgent() string {
+	return fmt.Sprintf("sentry-go %s (Sierra Softworks; github.com/SierraSoftworks/sentry-go)", version)
+}
+
 func (t *httpTransport) parseDSN(dsn string) (url, authHeader string, err error) {
 	d, err := newDSN(dsn)
 	if err != nil {
@@ -164,6 +164,10 @@ func TestHTTPTransport(t *testing.T) {
 		}
 	})
 
+	t.Run("Rate Limiting", func(t *testing.T) {
+		testTransportRateLimiting(t, newHTTPTransport())
+	})
+
 	t.Run("parseDSN()", func(t *testing.T) {
 		cases := []struct {
 			Name       string
@@ -0,0 +1,110 @@
+package sentry
+
+import "sync"
+
+// A Hub pairs a Client with a stack of Scopes, allowing you to accumulate
+// state for the duration of an operation and then discard it when the
+// operation completes. Events captured through the hub include the state
+// of the scope at the top of its stack.
+//
+// Since a hub has a single stack of scopes, it should only be used by one
+// goroutine at a time. If you need to capture events concurrently, for
+// example while handling requests, use Clone() to give each goroutine or
+// request its own hub.
+type Hub interface {
+	// Client returns the client which is used to send events.
+	Client() Client
+
+	// Scope returns the scope at the top of the hub's stack.
+	Scope() Scope
+
+	// PushScope adds a copy of the current scope to the top of the
+	// stack and returns it, allowing you to modify it without affecting
+	// the scopes beneath it.
+	PushScope() Scope
+
+	// PopScope removes the scope at the top of the stack. The hub will
+	// always keep at least one scope, so popping the last scope will
+	// have no effect.
+	PopScope()
+
+	// WithScope pushes a new scope for the duration of the provided
+	// function, popping it again once the function returns.
+	WithScope(fn func(scope Scope))
+
+	// Capture will queue an event for sending to Sentry using the hub's
+	// client and including the state of the current scope.
+	Capture(options ...Option) QueuedEvent
+
+	// Clone creates a new hub which uses the same client, starting with
+	// a copy of the current scope. It can then be used independently of
+	// this hub, including from another goroutine.
+	Clone() Hub
+}
+
+// NewHub creates a new Hub which uses the provided client to send events,
+// or the DefaultClient() if it is nil, starting with an empty scope.
+func NewHub(cl Client) Hub {
+	if cl == nil {
+		cl = DefaultClient()
+	}
+
+	return &hub{
+		client: cl,
+		scopes: []Scope{NewScope()},
+	}
+}
+
+type hub struct {
+	client Client
+	scopes []Scope
+
+	mutex sync.RWMutex
+}
+
+func (h *hub) Client() Client {
+	return h.client
+}
+
+func (h *hub) Scope() Scope {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.scopes[len(h.scopes)-1]
+}
+
+func (h *hub) PushScope() Scope {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	s := h.scopes[len(h.scopes)-1].Clone()
+	h.scopes = append(h.scopes, s)
+	return s
+}
+
+func (h *hub) PopScope() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if len(h.scopes) > 1 {
+		h.scopes = h.scopes[:len(h.scopes)-1]
+	}
+}
+
+func (h *hub) WithScope(fn func(scope Scope)) {
+	s := h.PushScope()
+	defer h.PopScope()
+
+	fn(s)
+}
+
+func (h *hub) Capture(options ...Option) QueuedEvent {
+	return h.client.Capture(append([]Option{h.Scope()}, options...)...)
+}
+
+func (h *hub) Clone() Hub {
+	return &hub{
+		client: h.client,
+		scopes: []Scope{h.Scope().Clone()},
+	}
+}
@@ -0,0 +1,162 @@
+package sentry
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewHub() {
+	hub := NewHub(NewClient())
+
+	hub.Scope().SetTag("service", "billing")
+
+	hub.WithScope(func(scope Scope) {
+		// Changes made to this scope are discarded once the function returns
+		scope.SetUser(&UserInfo{ID: "1234"})
+		scope.Breadcrumbs().NewDefault(nil).WithMessage("Charging card")
+
+		hub.Capture(Message("This event includes the user and breadcrumb"))
+	})
+
+	hub.Capture(Message("This event only includes the service tag"))
+}
+
+func TestHub(t *testing.T) {
+	tr := &testFlakyTransport{}
+	q := NewSequentialSendQueue(10)
+	defer q.Shutdown(true)
+
+	cl := NewClient(UseTransport(tr), UseSendQueue(q))
+
+	lastPacket := func(t *testing.T) Packet {
+		tr.mutex.Lock()
+		defer tr.mutex.Unlock()
+
+		require.NotEmpty(t, tr.sent, "an event should have been sent")
+		return tr.sent[len(tr.sent)-1]
+	}
+
+	h := NewHub(cl)
+	require.NotNil(t, h, "it should not return nil")
+	assert.Equal(t, cl, h.Client(), "it should use the provided client")
+	assert.NotNil(t, h.Scope(), "it should start with a scope")
+
+	t.Run("NewHub()", func(t *testing.T) {
+		assert.Equal(t, DefaultClient(), NewHub(nil).Client(), "it should use the default client if none is provided")
+	})
+
+	t.Run("PushScope()", func(t *testing.T) {
+		h := NewHub(cl)
+		root := h.Scope().SetTag("a", "1")
+
+		s := h.PushScope()
+		require.NotNil(t, s, "it should return the new scope")
+		assert.Equal(t, s, h.Scope(), "the new scope should be the current scope")
+		assert.False(t, root == s, "it should create a new scope")
+
+		s.SetTag("b", "2")
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, NewPacket().SetOptions(s).Tags(), "it should inherit the parent scope's state")
+		assert.Equal(t, map[string]string{"a": "1"}, NewPacket().SetOptions(root).Tags(), "it should not modify the parent scope")
+	})
+
+	t.Run("PopScope()", func(t *testing.T) {
+		h := NewHub(cl)
+		root := h.Scope()
+
+		h.PushScope()
+		h.PopScope()
+		assert.Equal(t, root, h.Scope(), "it should restore the parent scope")
+
+		h.PopScope()
+		assert.Equal(t, root, h.Scope(), "it should never remove the last scope")
+	})
+
+	t.Run("WithScope()", func(t *testing.T) {
+		h := NewHub(cl)
+		root := h.Scope()
+
+		called := false
+		h.WithScope(func(s Scope) {
+			called = true
+			assert.Equal(t, s, h.Scope(), "the scope should be the current scope")
+			assert.False(t, root == s, "it should be a new scope")
+		})
+
+		assert.True(t, called, "it should call the function")
+		assert.Equal(t, root, h.Scope(), "it should pop the scope once the function returns")
+
+		assert.Panics(t, func() {
+			h.WithScope(func(s Scope) {
+				panic("test")
+			})
+		})
+		assert.Equal(t, root, h.Scope(), "it should pop the scope if the function panics")
+	})
+
+	t.Run("Capture()", func(t *testing.T) {
+		h := NewHub(cl)
+		h.Scope().SetTag("a", "1")
+
+		h.WithScope(func(s Scope) {
+			s.SetTag("b", "2")
+			assert.Nil(t, h.Capture(Message("test"), Tags(map[string]string{"c": "3"})).Error(), "the event should have been sent")
+			assert.Equal(t, map[string]string{"a": "1", "b": "2", "c": "3"}, lastPacket(t).Tags(), "it should include the current scope")
+		})
+
+		assert.Nil(t, h.Capture(Message("test")).Error(), "the event should have been sent")
+		assert.Equal(t, map[string]string{"a": "1"}, lastPacket(t).Tags(), "it should not include popped scopes")
+	})
+
+	t.Run("Clone()", func(t *testing.T) {
+		h := NewHub(cl)
+		h.Scope().SetTag("a", "1")
+
+		c := h.Clone()
+		require.NotNil(t, c, "it should not return nil")
+		assert.Equal(t, cl, c.Client(), "it should use the same client")
+		assert.False(t, h.Scope() == c.Scope(), "it should copy the current scope")
+
+		c.Scope().SetTag("b", "2")
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, NewPacket().SetOptions(c.Scope()).Tags(), "it should inherit the current scope's state")
+		assert.Equal(t, map[string]string{"a": "1"}, NewPacket().SetOptions(h.Scope()).Tags(), "it should not modify the original hub's scope")
+
+		t.Run("Concurrent", func(t *testing.T) {
+			h := NewHub(cl)
+
+			// Every goroutine pushes its scope before any of them capture
+			// an event, so that they would see each other's scopes if they
+			// shared a stack.
+			var wg, pushed sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				pushed.Add(1)
+				go func(id string) {
+					defer wg.Done()
+
+					h := h.Clone()
+					h.WithScope(func(s Scope) {
+						s.SetTag("request", id)
+						pushed.Done()
+						pushed.Wait()
+
+						var p Packet
+						h.Capture(Message("test"), BeforeSend(func(packet Packet) Packet {
+							p = packet
+							return packet
+						})).Wait()
+
+						if assert.NotNil(t, p, "the event should have been captured") {
+							assert.Equal(t, id, p.Tags()["request"], "it should use the goroutine's own scope")
+						}
+					})
+				}(fmt.Sprint(i))
+			}
+
+			wg.Wait()
+		})
+	})
+}
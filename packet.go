@@ -15,6 +15,31 @@ type Packet interface {
 	// client with the options you wish to override, however there are
 	// situations where this is a cleaner solution.
 	Clone() Packet
+
+	// Get will return the option with the given class name from this
+	// packet, or nil if it has not been set. Keep in mind that the
+	// returned option is shared with the packet, so you should use
+	// SetOptions() rather than modifying it if you wish to change it.
+	Get(className string) Option
+
+	// Level returns the severity level of this packet, or an empty
+	// Severity if one has not been set.
+	Level() Severity
+
+	// Tags returns a copy of the tags which have been set on this
+	// packet, or nil if there are none.
+	Tags() map[string]string
+
+	// Exceptions returns copies of the exceptions which have been
+	// attached to this packet, from the root cause to the outermost
+	// error. Their mechanisms and stacktraces are copied as well,
+	// except for custom StackTraceOption implementations which are
+	// shared with the packet.
+	Exceptions() []ExceptionInfo
+
+	// Message returns the message which has been set on this packet,
+	// formatted with its parameters if any were provided.
+	Message() string
 }
 
 type packet map[string]Option
@@ -36,6 +61,65 @@ func (p packet) Clone() Packet {
 	return &np
 }
 
+func (p packet) Get(className string) Option {
+	if opt, ok := p[className]; ok {
+		return opt
+	}
+
+	return nil
+}
+
+func (p packet) Level() Severity {
+	if opt, ok := p["level"].(*levelOption); ok {
+		return opt.severity
+	}
+
+	return ""
+}
+
+func (p packet) Tags() map[string]string {
+	opt, ok := p["tags"].(*tagsOption)
+	if !ok {
+		return nil
+	}
+
+	tags := make(map[string]string, len(opt.tags))
+	for k, v := range opt.tags {
+		tags[k] = v
+	}
+
+	return tags
+}
+
+func (p packet) Exceptions() []ExceptionInfo {
+	opt, ok := p["exception"].(*exceptionOption)
+	if !ok {
+		return nil
+	}
+
+	exceptions := make([]ExceptionInfo, 0, len(opt.Exceptions))
+	for _, ex := range opt.Exceptions {
+		if ex != nil {
+			exceptions = append(exceptions, ex.clone())
+		}
+	}
+
+	return exceptions
+}
+
+func (p packet) Message() string {
+	opt, ok := p["sentry.interfaces.Message"].(*messageOption)
+	if !ok {
+		return ""
+	}
+
+	if opt.Formatted != "" {
+		return opt.Formatted
+	}
+
+	return opt.Message
+}
+
 func (p packet) SetOptions(options ...Option) Packet {
 	for _, opt := range options {
 		p.setOption(opt)
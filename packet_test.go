@@ -3,9 +3,11 @@ package sentry
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func ExamplePacket() {
@@ -23,6 +25,31 @@ func ExamplePacket() {
 	)
 }
 
+func ExamplePacket_Get() {
+	cl := NewClient(
+		BeforeSend(func(packet Packet) Packet {
+			// You can inspect the contents of a packet without
+			// needing to serialize it
+			if packet.Level() == Debug {
+				return nil
+			}
+
+			if _, ok := packet.Tags()["internal"]; ok {
+				return packet.SetOptions(Unset("user"))
+			}
+
+			// Or retrieve any option by its class name
+			if packet.Get("user") != nil {
+				fmt.Println("event has user information")
+			}
+
+			return packet
+		}),
+	)
+
+	cl.Capture(Message("Example event"))
+}
+
 func TestPacket(t *testing.T) {
 	p := NewPacket()
 	assert.NotNil(t, p, "should return a non-nil packet")
@@ -109,6 +136,72 @@ func TestPacket(t *testing.T) {
 		assert.Equal(t, p, p.Clone(), "the clone should copy any options across")
 	})
 
+	t.Run("Get()", func(t *testing.T) {
+		p := NewPacket()
+		assert.Nil(t, p.Get("test"), "it should return nil if the option has not been set")
+
+		opt := &testOption{}
+		p.SetOptions(opt)
+		assert.Equal(t, opt, p.Get("test"), "it should return the option if it has been set")
+	})
+
+	t.Run("Level()", func(t *testing.T) {
+		assert.Equal(t, Severity(""), NewPacket().Level(), "it should return an empty severity if the level has not been set")
+		assert.Equal(t, Warning, NewPacket().SetOptions(Level(Warning)).Level(), "it should return the level if it has been set")
+	})
+
+	t.Run("Tags()", func(t *testing.T) {
+		assert.Nil(t, NewPacket().Tags(), "it should return nil if no tags have been set")
+
+		p := NewPacket().SetOptions(
+			Tags(map[string]string{"a": "1"}),
+			Tags(map[string]string{"b": "2"}),
+		)
+
+		tags := p.Tags()
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, tags, "it should return the merged tags")
+
+		tags["c"] = "3"
+		assert.NotContains(t, p.Tags(), "c", "it should return a copy of the tags")
+	})
+
+	t.Run("Exceptions()", func(t *testing.T) {
+		assert.Empty(t, NewPacket().Exceptions(), "it should return no exceptions if none have been set")
+
+		p := NewPacket().SetOptions(ExceptionForError(fmt.Errorf("example error")))
+
+		exceptions := p.Exceptions()
+		require.Len(t, exceptions, 1, "it should return the exceptions")
+		assert.Equal(t, "example error", exceptions[0].Value, "it should return the exception's details")
+
+		exceptions[0].Value = "modified"
+		assert.Equal(t, "example error", p.Exceptions()[0].Value, "it should return copies of the exceptions")
+
+		ex := NewExceptionInfo()
+		ex.MechanismInfo = NewExceptionMechanism("panic", false)
+		ex.StackTrace = &stackTraceOption{
+			Frames: stackTraceFrames{{Function: "example"}},
+		}
+
+		p = NewPacket().SetOptions(&exceptionOption{Exceptions: []*ExceptionInfo{ex}})
+
+		exceptions = p.Exceptions()
+		require.Len(t, exceptions, 1, "it should return the exceptions")
+		*exceptions[0].MechanismInfo.Handled = true
+		exceptions[0].MechanismInfo.Type = "modified"
+		exceptions[0].StackTrace.(*stackTraceOption).Frames[0].Function = "modified"
+
+		assert.Equal(t, "panic", ex.MechanismInfo.Type, "it should return copies of the mechanisms")
+		assert.False(t, *ex.MechanismInfo.Handled, "it should return copies of the mechanisms")
+		assert.Equal(t, "example", ex.StackTrace.(*stackTraceOption).Frames[0].Function, "it should return copies of the stacktraces")
+	})
+
+	t.Run("Message()", func(t *testing.T) {
+		assert.Equal(t, "", NewPacket().Message(), "it should return an empty string if no message has been set")
+		assert.Equal(t, "test", NewPacket().SetOptions(Message("test")).Message(), "it should return the message")
+		assert.Equal(t, "test 1", NewPacket().SetOptions(Message("test %d", 1)).Message(), "it should return the formatted message")
+	})
+
 	t.Run("MarshalJSON()", func(t *testing.T) {
 		p := NewPacket()
 		p.SetOptions(&testOption{})
@@ -0,0 +1,19 @@
+package sentry
+
+// NewParallelSendQueue creates a new send queue which uses the given number
+// of workers to send events concurrently, buffering up to buffer events
+// while they wait to be sent. This is useful for high throughput services
+// where a single slow request would otherwise hold up every other event.
+//
+// When the queue is shutdown with wait set to true, any events which are
+// still buffered will be sent before Shutdown returns. Otherwise they will
+// be completed with an ErrSendQueueShutdown error.
+func NewParallelSendQueue(workers, buffer int) SendQueue {
+	return &parallelSendQueue{
+		newWorkerSendQueue("parallel send queue", workers, buffer),
+	}
+}
+
+type parallelSendQueue struct {
+	*workerSendQueue
+}
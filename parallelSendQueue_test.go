@@ -0,0 +1,261 @@
+package sentry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewParallelSendQueue() {
+	cl := NewClient(
+		// Send up to 5 events at a time, buffering up to 1000 events
+		// while they wait to be sent.
+		UseSendQueue(NewParallelSendQueue(5, 1000)),
+	)
+
+	cl.Capture(
+		Message("Sent using a parallel send queue"),
+	)
+}
+
+func TestParallelSendQueue(t *testing.T) {
+	q := NewParallelSendQueue(2, 10)
+	require.NotNil(t, q, "the queue should not be nil")
+	assert.Implements(t, (*SendQueue)(nil), q, "it should implement the SendQueue interface")
+	defer q.Shutdown(true)
+
+	require.IsType(t, &parallelSendQueue{}, q, "it should actually be a *parallelSendQueue")
+
+	waitForStart := func(t *testing.T, tr *testBlockingTransport, count int) {
+		for i := 0; i < count; i++ {
+			select {
+			case <-tr.started:
+			case <-time.After(100 * time.Millisecond):
+				t.Fatalf("timed out waiting for %d events to start sending, only %d started", count, i)
+			}
+		}
+	}
+
+	t.Run("Send()", func(t *testing.T) {
+		t.Run("Normal", func(t *testing.T) {
+			q := NewParallelSendQueue(1, 10)
+			defer q.Shutdown(true)
+
+			transport := testNewTestTransport()
+			cfg := NewClient(UseTransport(transport)).(Config)
+			p := NewPacket()
+
+			e := q.Enqueue(cfg, p)
+			require.NotNil(t, e, "the event should not be nil")
+
+			select {
+			case pp := <-transport.ch:
+				assert.Equal(t, p, pp, "the packet which was sent should match the packet which was enqueued")
+			case <-time.After(100 * time.Millisecond):
+				t.Fatal("timed out waiting for send")
+			}
+
+			assert.Nil(t, e.Error(), "there should have been no error sending the event")
+			assert.Equal(t, 1, e.(AttemptCountingEvent).Attempts(), "the event should report the number of attempts made")
+		})
+
+		t.Run("Concurrent", func(t *testing.T) {
+			q := NewParallelSendQueue(3, 10)
+			defer q.Shutdown(true)
+
+			tr := testNewBlockingTransport()
+			cfg := NewClient(UseTransport(tr)).(Config)
+
+			events := []QueuedEvent{}
+			for i := 0; i < 3; i++ {
+				events = append(events, q.Enqueue(cfg, NewPacket()))
+			}
+
+			waitForStart(t, tr, 3)
+			tr.Release()
+
+			for _, e := range events {
+				assert.Nil(t, e.Error(), "there should have been no error sending the event")
+			}
+		})
+
+		t.Run("QueueFull", func(t *testing.T) {
+			q := NewParallelSendQueue(1, 0)
+			defer q.Shutdown(true)
+
+			tr := testNewBlockingTransport()
+			defer tr.Release()
+			cfg := NewClient(UseTransport(tr)).(Config)
+
+			// Give the worker time to start
+			time.Sleep(1 * time.Millisecond)
+
+			e1 := q.Enqueue(cfg, NewPacket())
+			waitForStart(t, tr, 1)
+
+			e2 := q.Enqueue(cfg, NewPacket())
+			assert.True(t, ErrSendQueueFull.IsInstance(e2.Error()), "the error should be of type ErrSendQueueFull")
+
+			tr.Release()
+			assert.Nil(t, e1.Error(), "the first event should have been sent")
+		})
+	})
+
+	t.Run("Flush()", func(t *testing.T) {
+		q := NewParallelSendQueue(2, 10)
+		defer q.Shutdown(true)
+
+		assert.Implements(t, (*FlushableSendQueue)(nil), q, "it should implement the FlushableSendQueue interface")
+
+		tr := testNewBlockingTransport()
+		defer tr.Release()
+		cl := NewClient(UseTransport(tr), UseSendQueue(q))
+
+		assert.Nil(t, q.(FlushableSendQueue).Flush(context.Background()), "it should not block if there are no events")
+
+		e1 := cl.Capture(Message("event 1"))
+		e2 := cl.Capture(Message("event 2"))
+		waitForStart(t, tr, 2)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, q.(FlushableSendQueue).Flush(ctx), "it should time out if the events cannot be sent")
+
+		tr.Release()
+		assert.Nil(t, q.(FlushableSendQueue).Flush(context.Background()), "it should not return an error once the events have been sent")
+		assert.Nil(t, e1.Error(), "the first event should have been sent")
+		assert.Nil(t, e2.Error(), "the second event should have been sent")
+	})
+
+	t.Run("Shutdown()", func(t *testing.T) {
+		t.Run("Drain", func(t *testing.T) {
+			q := NewParallelSendQueue(1, 10)
+
+			tr := testNewBlockingTransport()
+			cfg := NewClient(UseTransport(tr)).(Config)
+
+			events := []QueuedEvent{}
+			for i := 0; i < 3; i++ {
+				events = append(events, q.Enqueue(cfg, NewPacket()))
+			}
+
+			waitForStart(t, tr, 1)
+
+			done := make(chan struct{})
+			go func() {
+				q.Shutdown(true)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				t.Fatal("shutdown should wait for the buffered events to be sent")
+			case <-time.After(10 * time.Millisecond):
+			}
+
+			tr.Release()
+
+			select {
+			case <-done:
+			case <-time.After(100 * time.Millisecond):
+				t.Fatal("timed out waiting for the queue to shutdown")
+			}
+
+			for _, e := range events {
+				assert.Nil(t, e.Error(), "buffered events should be sent when draining the queue")
+			}
+
+			e := q.Enqueue(cfg, NewPacket())
+			assert.True(t, ErrSendQueueShutdown.IsInstance(e.Error()), "the error should be of type ErrSendQueueShutdown")
+		})
+
+		t.Run("Abort", func(t *testing.T) {
+			q := NewParallelSendQueue(1, 10)
+
+			tr := testNewBlockingTransport()
+			cfg := NewClient(UseTransport(tr)).(Config)
+
+			e1 := q.Enqueue(cfg, NewPacket())
+			waitForStart(t, tr, 1)
+
+			e2 := q.Enqueue(cfg, NewPacket())
+			e3 := q.Enqueue(cfg, NewPacket())
+
+			q.Shutdown(false)
+			tr.Release()
+
+			assert.Nil(t, e1.Error(), "the event which was being sent should complete normally")
+			assert.True(t, ErrSendQueueShutdown.IsInstance(e2.Error()), "buffered events should fail with ErrSendQueueShutdown")
+			assert.True(t, ErrSendQueueShutdown.IsInstance(e3.Error()), "buffered events should fail with ErrSendQueueShutdown")
+		})
+
+		t.Run("Concurrent", func(t *testing.T) {
+			q := NewParallelSendQueue(4, 100)
+			cfg := NewClient(UseTransport(&testFlakyTransport{})).(Config)
+
+			events := make(chan QueuedEvent, 100)
+			wg := sync.WaitGroup{}
+			for i := 0; i < 10; i++ {
+				wg.Add(2)
+
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 10; j++ {
+						events <- q.Enqueue(cfg, NewPacket())
+					}
+				}()
+
+				go func() {
+					defer wg.Done()
+					q.Shutdown(true)
+				}()
+			}
+
+			wg.Wait()
+			close(events)
+
+			for e := range events {
+				select {
+				case <-e.WaitChannel():
+				case <-time.After(100 * time.Millisecond):
+					t.Fatal("timed out waiting for event completion")
+				}
+
+				if err := e.Error(); err != nil {
+					assert.True(t, ErrSendQueueShutdown.IsInstance(err), "events should either be sent or fail with ErrSendQueueShutdown")
+				}
+			}
+		})
+	})
+}
+
+// testBlockingTransport blocks every send until it has been released.
+type testBlockingTransport struct {
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func testNewBlockingTransport() *testBlockingTransport {
+	return &testBlockingTransport{
+		started: make(chan struct{}, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (t *testBlockingTransport) Send(dsn string, packet Packet) error {
+	t.started <- struct{}{}
+	<-t.release
+	return nil
+}
+
+// Release unblocks all current and future sends.
+func (t *testBlockingTransport) Release() {
+	t.once.Do(func() {
+		close(t.release)
+	})
+}
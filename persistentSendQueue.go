@@ -0,0 +1,398 @@
+package sentry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NewPersistentSendQueue creates a SendQueue which spools every event it
+// receives to the provided directory before handing it to the wrapped queue
+// for delivery. Events are removed from disk once they have been sent, or
+// have failed for a reason which retrying would not fix, so anything which
+// was still queued when your process exited will be replayed the next time
+// a persistent queue is created for the same directory.
+//
+// The maxSize (in bytes) and maxAge limits control how much data will be
+// kept on disk, with the oldest events being discarded first. A limit of
+// zero disables that check.
+//
+// Replayed events are sent using the provided transport, since the one
+// which was originally configured for them cannot be stored on disk. If it
+// is nil, the default transport will be used instead.
+//
+// Events which fail for a reason that might not happen again, like being
+// rate limited or finding the wrapped queue full, are kept on disk but are
+// not retried until the next time a persistent queue is created for the
+// same directory.
+func NewPersistentSendQueue(dir string, maxSize int64, maxAge time.Duration, queue SendQueue, transport Transport) (SendQueue, error) {
+	if queue == nil {
+		return nil, errors.New("persistent send queue: no send queue provided")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "persistent send queue: failed to create spool directory")
+	}
+
+	q := &persistentSendQueue{
+		dir:       dir,
+		maxSize:   maxSize,
+		maxAge:    maxAge,
+		queue:     queue,
+		transport: transport,
+	}
+
+	if err := q.replay(); err != nil {
+		return nil, errors.Wrap(err, "persistent send queue: failed to replay spooled events")
+	}
+
+	return q, nil
+}
+
+type persistentSendQueue struct {
+	dir       string
+	maxSize   int64
+	maxAge    time.Duration
+	queue     SendQueue
+	transport Transport
+
+	// cleanup tracks the events whose spooled copies may still need to be
+	// removed, allowing Shutdown and Flush to wait for them.
+	cleanup pendingEvents
+
+	// files indexes the events which have been spooled to disk, from oldest
+	// to newest, and size is their total size. They are loaded when the
+	// queue is created so that we don't need to list the spool directory
+	// for every event.
+	files []spooledFile
+	size  int64
+	mutex sync.Mutex
+}
+
+// spooledEvent is the representation of an event which is written to disk.
+type spooledEvent struct {
+	DSN    string          `json:"dsn"`
+	Packet json.RawMessage `json:"packet"`
+}
+
+func (q *persistentSendQueue) Enqueue(cfg Config, packet Packet) QueuedEvent {
+	file, err := q.spool(cfg.DSN(), packet)
+	if err != nil {
+		// If we can't persist the event we still want to try and send it
+		return q.queue.Enqueue(cfg, packet)
+	}
+
+	return q.track(file, q.queue.Enqueue(cfg, packet))
+}
+
+func (q *persistentSendQueue) Shutdown(wait bool) {
+	q.queue.Shutdown(wait)
+
+	if wait {
+		q.cleanup.Wait(context.Background())
+	}
+}
+
+func (q *persistentSendQueue) Flush(ctx context.Context) error {
+	fq, ok := q.queue.(FlushableSendQueue)
+	if !ok {
+		err := errors.New("the wrapped send queue does not implement FlushableSendQueue")
+		return errors.Wrap(err, ErrSendQueueNotFlushable.Error())
+	}
+
+	if err := fq.Flush(ctx); err != nil {
+		return err
+	}
+
+	return q.cleanup.Wait(ctx)
+}
+
+// track removes the spooled copy of an event once it is no longer needed.
+func (q *persistentSendQueue) track(file string, e QueuedEvent) QueuedEvent {
+	q.cleanup.Add(e)
+
+	go func() {
+		defer q.cleanup.Done(e)
+
+		if err := e.Error(); err != nil && shouldRespool(err) {
+			return
+		}
+
+		q.mutex.Lock()
+		defer q.mutex.Unlock()
+
+		q.remove(file)
+	}()
+
+	return e
+}
+
+// shouldRespool determines whether an event which failed to send should be
+// kept on disk so that it can be sent again later.
+func shouldRespool(err error) bool {
+	return isRetryableError(err) ||
+		ErrRateLimited.IsInstance(err) ||
+		ErrSendQueueFull.IsInstance(err) ||
+		ErrSendQueueShutdown.IsInstance(err)
+}
+
+func (q *persistentSendQueue) spool(dsn string, p Packet) (string, error) {
+	data, err := encodeSpooledEvent(dsn, p)
+	if err != nil {
+		return "", err
+	}
+
+	if q.maxSize > 0 && int64(len(data)) > q.maxSize {
+		return "", errors.New("event is larger than the maximum spool size")
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if err := q.prune(int64(len(data))); err != nil {
+		return "", err
+	}
+
+	// The creation time is stored in the file's name, so we use the same
+	// precision here as when the index is loaded from disk.
+	created := time.Unix(0, time.Now().UnixNano())
+	name := fmt.Sprintf("%d", created.UnixNano())
+	if pp, ok := p.(*packet); ok {
+		name = fmt.Sprintf("%s-%s", name, pp.getEventID())
+	}
+
+	tmp, err := ioutil.TempFile(q.dir, ".spool-")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create spool file")
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", errors.Wrap(err, "failed to write spool file")
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", errors.Wrap(err, "failed to write spool file")
+	}
+
+	file := filepath.Join(q.dir, name+".json")
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		os.Remove(tmp.Name())
+		return "", errors.Wrap(err, "failed to write spool file")
+	}
+
+	q.files = append(q.files, spooledFile{
+		path:    file,
+		size:    int64(len(data)),
+		created: created,
+	})
+	q.size += int64(len(data))
+
+	return file, nil
+}
+
+// encodeSpooledEvent serializes an event in the spooledEvent format. The
+// packet is written directly into the result, since marshalling it as a
+// json.RawMessage would have it validated and copied a second time.
+func encodeSpooledEvent(dsn string, p Packet) ([]byte, error) {
+	packetData, err := json.Marshal(p)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize packet")
+	}
+
+	dsnData, err := json.Marshal(dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize event")
+	}
+
+	data := make([]byte, 0, len(dsnData)+len(packetData)+len(`{"dsn":,"packet":}`))
+	data = append(data, `{"dsn":`...)
+	data = append(data, dsnData...)
+	data = append(data, `,"packet":`...)
+	data = append(data, packetData...)
+	data = append(data, '}')
+
+	return data, nil
+}
+
+// replay loads the index of events which were spooled to disk by a previous
+// instance of the queue and enqueues them.
+func (q *persistentSendQueue) replay() error {
+	q.mutex.Lock()
+	files, err := q.spooledFiles()
+	if err == nil {
+		q.files = files
+		for _, file := range files {
+			q.size += file.size
+		}
+
+		err = q.prune(0)
+		files = append([]spooledFile{}, q.files...)
+	}
+	q.mutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		packet, dsn, err := readSpooledEvent(file.path)
+		if err != nil {
+			q.mutex.Lock()
+			q.remove(file.path)
+			q.mutex.Unlock()
+			continue
+		}
+
+		cl := NewClient(DSN(dsn), UseTransport(q.transport))
+		q.track(file.path, q.queue.Enqueue(cl.(Config), packet))
+	}
+
+	return nil
+}
+
+// readSpooledEvent loads an event which was spooled to disk.
+func readSpooledEvent(path string) (Packet, string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var event spooledEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, "", err
+	}
+
+	packet, err := newSpooledPacket(event.Packet)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return packet, event.DSN, nil
+}
+
+// prune removes spooled events which have exceeded the maximum age, as well
+// as the oldest events if there is not enough room for another reserve bytes.
+// It must be called while holding the queue's mutex.
+func (q *persistentSendQueue) prune(reserve int64) error {
+	for _, file := range append([]spooledFile{}, q.files...) {
+		expired := q.maxAge > 0 && time.Since(file.created) > q.maxAge
+		if !expired && (q.maxSize <= 0 || q.size+reserve <= q.maxSize) {
+			continue
+		}
+
+		if err := q.remove(file.path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// remove deletes a spooled event and removes it from the index. It must be
+// called while holding the queue's mutex.
+func (q *persistentSendQueue) remove(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove spool file")
+	}
+
+	for i, file := range q.files {
+		if file.path == path {
+			q.files = append(q.files[:i], q.files[i+1:]...)
+			q.size -= file.size
+			break
+		}
+	}
+
+	return nil
+}
+
+type spooledFile struct {
+	path    string
+	size    int64
+	created time.Time
+}
+
+// spooledFiles lists the events which have been spooled to disk, from
+// oldest to newest.
+func (q *persistentSendQueue) spooledFiles() ([]spooledFile, error) {
+	entries, err := ioutil.ReadDir(q.dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list spool directory")
+	}
+
+	files := []spooledFile{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		created, err := strconv.ParseInt(strings.SplitN(strings.TrimSuffix(entry.Name(), ".json"), "-", 2)[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		files = append(files, spooledFile{
+			path:    filepath.Join(q.dir, entry.Name()),
+			size:    entry.Size(),
+			created: time.Unix(0, created),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].created.Before(files[j].created)
+	})
+
+	return files, nil
+}
+
+// newSpooledPacket rebuilds a packet from its serialized form, preserving
+// the exact JSON representation of each of its fields.
+func newSpooledPacket(data []byte) (Packet, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	p := packet{}
+	for class, value := range fields {
+		if class == "event_id" {
+			var id string
+			if err := json.Unmarshal(value, &id); err == nil {
+				if opt := EventID(id); opt != nil {
+					p[class] = opt
+					continue
+				}
+			}
+		}
+
+		p[class] = &spooledOption{class, value}
+	}
+
+	return &p, nil
+}
+
+type spooledOption struct {
+	class string
+	data  json.RawMessage
+}
+
+func (o *spooledOption) Class() string {
+	return o.class
+}
+
+func (o *spooledOption) MarshalJSON() ([]byte, error) {
+	return o.data, nil
+}
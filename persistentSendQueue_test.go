@@ -0,0 +1,286 @@
+package sentry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewPersistentSendQueue() {
+	q, err := NewPersistentSendQueue(
+		// The directory that events will be spooled to
+		"/var/spool/sentry",
+		// Keep at most 10MB of events on disk
+		10*1024*1024,
+		// And discard events which are more than a day old
+		24*time.Hour,
+		// Use a sequential queue to send the events
+		NewSequentialSendQueue(100),
+		// And replay events from previous runs using the default transport
+		nil,
+	)
+
+	if err != nil {
+		fmt.Println("failed to create persistent send queue: ", err)
+		return
+	}
+
+	cl := NewClient(
+		UseSendQueue(q),
+	)
+
+	cl.Capture(
+		Message("This event will be replayed if we exit before it is sent"),
+	)
+}
+
+func TestPersistentSendQueue(t *testing.T) {
+	testDir := func(t *testing.T) string {
+		dir, err := ioutil.TempDir("", "sentry-go-spool-")
+		require.Nil(t, err, "we should be able to create a temporary directory")
+		return dir
+	}
+
+	spooledFiles := func(t *testing.T, dir string) []spooledFile {
+		q := &persistentSendQueue{dir: dir}
+		files, err := q.spooledFiles()
+		require.Nil(t, err, "we should be able to list the spooled files")
+		return files
+	}
+
+	dsn := "https://key@example.com/sentry/1"
+
+	t.Run("NewPersistentSendQueue()", func(t *testing.T) {
+		dir := testDir(t)
+		defer os.RemoveAll(dir)
+
+		_, err := NewPersistentSendQueue(dir, 0, 0, nil, nil)
+		assert.NotNil(t, err, "it should return an error if no queue is provided")
+
+		q, err := NewPersistentSendQueue(filepath.Join(dir, "nested"), 0, 0, NewSequentialSendQueue(10), nil)
+		require.Nil(t, err, "it should not return an error")
+		require.NotNil(t, q, "the queue should not be nil")
+		assert.Implements(t, (*SendQueue)(nil), q, "it should implement the SendQueue interface")
+		assert.IsType(t, &persistentSendQueue{}, q, "it should actually be a *persistentSendQueue")
+		defer q.Shutdown(true)
+
+		assert.DirExists(t, filepath.Join(dir, "nested"), "it should create the spool directory")
+	})
+
+	t.Run("Enqueue()", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			dir := testDir(t)
+			defer os.RemoveAll(dir)
+
+			q, err := NewPersistentSendQueue(dir, 0, 0, NewSequentialSendQueue(10), nil)
+			require.Nil(t, err, "it should not return an error")
+			defer q.Shutdown(true)
+
+			tr := &testFlakyTransport{}
+			cl := NewClient(DSN(dsn), UseTransport(tr), UseSendQueue(q))
+
+			e := cl.Capture(Message("test"))
+			assert.Nil(t, e.Error(), "the event should have been sent")
+			assert.Equal(t, 1, tr.calls, "the transport should have been used to send the event")
+
+			require.Nil(t, q.(FlushableSendQueue).Flush(context.Background()), "we should be able to flush the queue")
+			assert.Empty(t, spooledFiles(t, dir), "the event should be removed from disk once it has been sent")
+		})
+
+		t.Run("Permanent Failure", func(t *testing.T) {
+			dir := testDir(t)
+			defer os.RemoveAll(dir)
+
+			q, err := NewPersistentSendQueue(dir, 0, 0, NewSequentialSendQueue(10), nil)
+			require.Nil(t, err, "it should not return an error")
+			defer q.Shutdown(true)
+
+			tr := &testFlakyTransport{failures: 1, err: &httpStatusError{400}}
+			cl := NewClient(DSN(dsn), UseTransport(tr), UseSendQueue(q))
+
+			e := cl.Capture(Message("test"))
+			assert.NotNil(t, e.Error(), "the event should have failed")
+
+			require.Nil(t, q.(FlushableSendQueue).Flush(context.Background()), "we should be able to flush the queue")
+			assert.Empty(t, spooledFiles(t, dir), "the event should be removed from disk since retrying it would not help")
+		})
+
+		t.Run("Transient Failure", func(t *testing.T) {
+			dir := testDir(t)
+			defer os.RemoveAll(dir)
+
+			q, err := NewPersistentSendQueue(dir, 0, 0, NewSequentialSendQueue(10), nil)
+			require.Nil(t, err, "it should not return an error")
+			defer q.Shutdown(true)
+
+			tr := &testFlakyTransport{failures: 1, err: &httpStatusError{503}}
+			cl := NewClient(DSN(dsn), UseTransport(tr), UseSendQueue(q))
+
+			e := cl.Capture(Message("test"))
+			assert.NotNil(t, e.Error(), "the event should have failed")
+
+			require.Nil(t, q.(FlushableSendQueue).Flush(context.Background()), "we should be able to flush the queue")
+			assert.Len(t, spooledFiles(t, dir), 1, "the event should be kept on disk so that it can be retried")
+		})
+
+		t.Run("Max Size", func(t *testing.T) {
+			dir := testDir(t)
+			defer os.RemoveAll(dir)
+
+			q, err := NewPersistentSendQueue(dir, 2048, 0, NewSequentialSendQueue(10), nil)
+			require.Nil(t, err, "it should not return an error")
+			defer q.Shutdown(true)
+
+			tr := &testFlakyTransport{failures: 100, err: &httpStatusError{503}}
+			cl := NewClient(DSN(dsn), UseTransport(tr), UseSendQueue(q))
+
+			for i := 0; i < 20; i++ {
+				assert.NotNil(t, cl.Capture(Message("test %d", i)).Error(), "the event should have failed")
+			}
+
+			files := spooledFiles(t, dir)
+			assert.NotEmpty(t, files, "some events should be kept on disk")
+
+			size := int64(0)
+			for _, file := range files {
+				size += file.size
+			}
+			assert.True(t, size <= 2048, "the spooled events should not exceed the maximum size")
+
+			pq := q.(*persistentSendQueue)
+			pq.mutex.Lock()
+			assert.Equal(t, files, pq.files, "it should keep an index of the spooled events")
+			assert.Equal(t, size, pq.size, "it should keep track of the size of the spooled events")
+			pq.mutex.Unlock()
+
+			e := cl.Capture(Message("%s", string(make([]byte, 4096))))
+			assert.NotNil(t, e.Error(), "events which are too large to spool should still be sent")
+			assert.Equal(t, 21, tr.calls, "the transport should have been used to send all events")
+		})
+	})
+
+	t.Run("Flush()", func(t *testing.T) {
+		dir := testDir(t)
+		defer os.RemoveAll(dir)
+
+		q, err := NewPersistentSendQueue(dir, 0, 0, NewSequentialSendQueue(10), nil)
+		require.Nil(t, err, "it should not return an error")
+		defer q.Shutdown(true)
+
+		tr := testNewTestTransport()
+		cl := NewClient(DSN(dsn), UseTransport(tr), UseSendQueue(q))
+		e := cl.Capture(Message("test"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, q.(FlushableSendQueue).Flush(ctx), "it should wait for the wrapped queue to be flushed")
+
+		<-tr.ch
+		assert.Nil(t, q.(FlushableSendQueue).Flush(context.Background()), "it should not return an error once the queue has been flushed")
+		assert.Nil(t, e.Error(), "the event should have been sent")
+		assert.Empty(t, spooledFiles(t, dir), "it should wait for the event to be removed from disk")
+
+		uq, err := NewPersistentSendQueue(dir, 0, 0, &struct{ SendQueue }{NewSequentialSendQueue(10)}, nil)
+		require.Nil(t, err, "it should not return an error")
+		defer uq.Shutdown(true)
+
+		err = uq.(FlushableSendQueue).Flush(context.Background())
+		assert.True(t, ErrSendQueueNotFlushable.IsInstance(err), "it should return an error if the wrapped queue cannot be flushed")
+	})
+
+	t.Run("Shutdown()", func(t *testing.T) {
+		dir := testDir(t)
+		defer os.RemoveAll(dir)
+
+		q, err := NewPersistentSendQueue(dir, 0, 0, NewSequentialSendQueue(10), nil)
+		require.Nil(t, err, "it should not return an error")
+
+		tr := &testFlakyTransport{}
+		cl := NewClient(DSN(dsn), UseTransport(tr), UseSendQueue(q))
+		e := cl.Capture(Message("test"))
+
+		q.Shutdown(true)
+		assert.Nil(t, e.Error(), "the event should have been sent")
+		assert.Empty(t, spooledFiles(t, dir), "it should wait for the event to be removed from disk")
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		tr := &testFlakyTransport{failures: 1, err: &httpStatusError{503}}
+
+		dir := testDir(t)
+		defer os.RemoveAll(dir)
+
+		q, err := NewPersistentSendQueue(dir, 0, 0, NewSequentialSendQueue(10), nil)
+		require.Nil(t, err, "it should not return an error")
+
+		cl := NewClient(DSN(dsn), UseTransport(tr), UseSendQueue(q))
+		e := cl.Capture(Message("test"), Tags(map[string]string{"replayed": "true"}))
+		assert.NotNil(t, e.Error(), "the event should have failed")
+		q.Shutdown(true)
+
+		require.Len(t, spooledFiles(t, dir), 1, "the event should be kept on disk")
+
+		// Add an event which has expired and should not be replayed
+		expired := filepath.Join(dir, fmt.Sprintf("%d.json", time.Now().Add(-2*time.Hour).UnixNano()))
+		require.Nil(t, ioutil.WriteFile(expired, []byte(`{"dsn":"","packet":{}}`), 0600), "we should be able to write an expired event")
+
+		// Add an event which is corrupt and should be removed
+		corrupt := filepath.Join(dir, fmt.Sprintf("%d.json", time.Now().UnixNano()))
+		require.Nil(t, ioutil.WriteFile(corrupt, []byte(`{`), 0600), "we should be able to write a corrupt event")
+
+		q, err = NewPersistentSendQueue(dir, 0, time.Hour, NewSequentialSendQueue(10), tr)
+		require.Nil(t, err, "it should not return an error")
+		defer q.Shutdown(true)
+
+		require.Nil(t, q.(FlushableSendQueue).Flush(context.Background()), "we should be able to flush the queue")
+		assert.Empty(t, spooledFiles(t, dir), "the replayed event should be removed from disk once it has been sent")
+
+		tr.mutex.Lock()
+		defer tr.mutex.Unlock()
+
+		require.Len(t, tr.sent, 2, "the event should have been replayed exactly once")
+		assert.Equal(t, testSerializePacket(t, tr.sent[0]), testSerializePacket(t, tr.sent[1]), "the replayed event should match the original")
+
+		if p, ok := tr.sent[1].(*packet); assert.True(t, ok, "the replayed packet should be a *packet") {
+			assert.Equal(t, e.EventID(), p.getEventID(), "the replayed packet should have the same event ID")
+		}
+	})
+
+	t.Run("encodeSpooledEvent()", func(t *testing.T) {
+		p := NewPacket().SetOptions(Message("test"), Level(Warning))
+
+		data, err := encodeSpooledEvent(dsn, p)
+		require.Nil(t, err, "it should not return an error")
+
+		var event spooledEvent
+		require.Nil(t, json.Unmarshal(data, &event), "it should produce valid JSON")
+		assert.Equal(t, dsn, event.DSN, "it should include the DSN")
+
+		sp, err := newSpooledPacket(event.Packet)
+		require.Nil(t, err, "it should include the packet")
+		assert.Equal(t, testSerializePacket(t, p), testSerializePacket(t, sp), "it should include the serialized packet")
+	})
+
+	t.Run("newSpooledPacket()", func(t *testing.T) {
+		_, err := newSpooledPacket([]byte(`[]`))
+		assert.NotNil(t, err, "it should return an error for invalid packets")
+
+		id, err := NewEventID()
+		require.Nil(t, err, "there should be no error creating an event ID")
+
+		p := NewPacket().SetOptions(EventID(id), Message("test"), Level(Warning))
+		data := testSerializePacket(t, p)
+
+		sp, err := newSpooledPacket([]byte(fmt.Sprintf(`{"event_id":%q,"level":"warning","sentry.interfaces.Message":{"message":"test"}}`, id)))
+		require.Nil(t, err, "it should not return an error")
+		assert.Equal(t, data, testSerializePacket(t, sp), "it should serialize in the same way as the original packet")
+	})
+}
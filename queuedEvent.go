@@ -1,5 +1,7 @@
 package sentry
 
+import "sync"
+
 // A QueuedEvent allows you to track the status of sending
 // an event to Sentry.
 type QueuedEvent interface {
@@ -9,6 +11,19 @@ type QueuedEvent interface {
 	Error() error
 }
 
+// An AttemptCountingEvent is a QueuedEvent which keeps track of the number
+// of attempts which were made to send it to Sentry. The events returned by
+// NewQueuedEvent implement it, which you can check with a type assertion.
+type AttemptCountingEvent interface {
+	QueuedEvent
+
+	// Attempts waits for the event to complete and returns the number
+	// of attempts which were made to send it to Sentry. It will be 0 if
+	// the event was never handed to a transport or if the SendQueue
+	// does not keep track of its delivery attempts.
+	Attempts() int
+}
+
 // QueuedEventInternal is an interface used by SendQueue
 // implementations to "complete" a queued event once it has
 // either been sent to Sentry, or sending failed with an error.
@@ -19,6 +34,12 @@ type QueuedEventInternal interface {
 	Complete(err error)
 }
 
+// attemptRecordingEvent is implemented by events which can record the
+// number of attempts which were made to send them before they are completed.
+type attemptRecordingEvent interface {
+	SetAttempts(attempts int)
+}
+
 // NewQueuedEvent is used by SendQueue implementations to expose
 // information about the events that they are sending to Sentry.
 func NewQueuedEvent(cfg Config, packet Packet) QueuedEvent {
@@ -31,12 +52,22 @@ func NewQueuedEvent(cfg Config, packet Packet) QueuedEvent {
 	return e
 }
 
+// newFailedEvent creates a QueuedEvent for a packet which will not be
+// handed to a SendQueue, completing it immediately with the given error.
+func newFailedEvent(cfg Config, packet Packet, err error) QueuedEvent {
+	e := NewQueuedEvent(cfg, packet)
+	e.(QueuedEventInternal).Complete(err)
+	return e
+}
+
 type queuedEvent struct {
-	cfg    Config
-	packet Packet
-	err    error
+	cfg      Config
+	packet   Packet
+	err      error
+	attempts int
 
-	wait chan struct{}
+	wait  chan struct{}
+	mutex sync.Mutex
 }
 
 func (e *queuedEvent) EventID() string {
@@ -72,6 +103,22 @@ func (e *queuedEvent) Error() error {
 	return e.Wait().(*queuedEvent).err
 }
 
+func (e *queuedEvent) Attempts() int {
+	e.Wait()
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.attempts
+}
+
+func (e *queuedEvent) SetAttempts(attempts int) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.attempts = attempts
+}
+
 func (e *queuedEvent) Packet() Packet {
 	return e.packet
 }
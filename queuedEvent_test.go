@@ -312,4 +312,36 @@ func TestQueuedEvent(t *testing.T) {
 		ei.Complete(nil)
 		assert.NotNil(t, e.Error(), "it shouldn't modify the status of the event after it has been set")
 	})
+
+	t.Run("Attempts()", func(t *testing.T) {
+		e := NewQueuedEvent(cfg, p)
+		require.NotNil(t, e, "the event should not be nil")
+
+		require.Implements(t, (*QueuedEventInternal)(nil), e, "it should implement the QueuedEventInternal interface")
+		require.Implements(t, (*AttemptCountingEvent)(nil), e, "it should implement the AttemptCountingEvent interface")
+		require.Implements(t, (*attemptRecordingEvent)(nil), e, "it should implement the attemptRecordingEvent interface")
+		ei := e.(QueuedEventInternal)
+
+		ch := make(chan int)
+		go func() {
+			ch <- e.(AttemptCountingEvent).Attempts()
+		}()
+
+		ei.(attemptRecordingEvent).SetAttempts(3)
+
+		select {
+		case <-ch:
+			t.Fatal("it should wait for the event to complete")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		ei.Complete(nil)
+
+		select {
+		case attempts := <-ch:
+			assert.Equal(t, 3, attempts, "it should return the number of attempts")
+		case <-time.After(100 * time.Millisecond):
+			t.Error("timed out after 100ms with no response")
+		}
+	})
 }
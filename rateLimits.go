@@ -0,0 +1,175 @@
+package sentry
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ErrRateLimited is used when an event is not sent because the Sentry
+	// server has asked us to back off, either in response to this event or
+	// because an earlier response placed us inside a rate limit window.
+	ErrRateLimited = ErrType("sentry: rate limited")
+)
+
+// rateLimitWindowError is used by transports which did not attempt to send
+// an event because an earlier response placed us inside a rate limit window.
+type rateLimitWindowError struct {
+	Until time.Time
+}
+
+func (e *rateLimitWindowError) Error() string {
+	return fmt.Sprintf("backing off until %s", e.Until.UTC().Format(time.RFC3339))
+}
+
+// isRateLimitWindowError determines whether an error was returned without
+// attempting to send an event because of an active rate limit window.
+func isRateLimitWindowError(err error) bool {
+	var windowErr *rateLimitWindowError
+	return errors.As(err, &windowErr)
+}
+
+// defaultRetryAfter is used when the server responds with a 429 status
+// code but does not tell us how long we should wait before trying again.
+const defaultRetryAfter = 60 * time.Second
+
+// rateLimitCategoryError is the data category which Sentry uses for the
+// events sent by this library.
+const rateLimitCategoryError = "error"
+
+// rateLimits keeps track of the backoff windows which Sentry has asked us
+// to respect for each DSN and data category. Its zero value is ready to use
+// and it is safe for concurrent use.
+type rateLimits struct {
+	limits map[rateLimitKey]time.Time
+	mutex  sync.RWMutex
+}
+
+type rateLimitKey struct {
+	dsn      string
+	category string
+}
+
+// Until returns the time at which the rate limit for the given DSN and
+// category expires, and whether that time lies in the future.
+func (r *rateLimits) Until(dsn, category string) (time.Time, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	now := time.Now()
+	until := time.Time{}
+
+	// A limit with an empty category applies to all categories.
+	for _, c := range []string{"", category} {
+		if t, ok := r.limits[rateLimitKey{dsn, c}]; ok && t.After(until) {
+			until = t
+		}
+	}
+
+	return until, until.After(now)
+}
+
+// Update records any rate limits that the server has communicated in its
+// response to a request made using the given DSN.
+func (r *rateLimits) Update(dsn string, res *http.Response) {
+	now := time.Now()
+
+	if header := res.Header.Get("X-Sentry-Rate-Limits"); header != "" {
+		for _, limit := range parseRateLimits(header) {
+			for _, category := range limit.categories {
+				r.set(dsn, category, now.Add(limit.retryAfter))
+			}
+		}
+
+		return
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		r.set(dsn, "", now.Add(parseRetryAfter(res.Header.Get("Retry-After"), now)))
+	}
+}
+
+func (r *rateLimits) set(dsn, category string, until time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.limits == nil {
+		r.limits = map[rateLimitKey]time.Time{}
+	}
+
+	key := rateLimitKey{dsn, category}
+	if existing, ok := r.limits[key]; ok && existing.After(until) {
+		return
+	}
+
+	r.limits[key] = until
+}
+
+type rateLimit struct {
+	retryAfter time.Duration
+	categories []string
+}
+
+// parseRateLimits parses the contents of an X-Sentry-Rate-Limits header,
+// which is a comma separated list of limits with the form
+// `retry_after:categories:scope:reason_code`. An empty list of categories
+// indicates that the limit applies to all categories.
+func parseRateLimits(header string) []rateLimit {
+	limits := []rateLimit{}
+
+	for _, entry := range strings.Split(header, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) < 2 {
+			continue
+		}
+
+		seconds, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			continue
+		}
+
+		limit := rateLimit{
+			retryAfter: time.Duration(seconds * float64(time.Second)),
+			categories: []string{},
+		}
+
+		for _, category := range strings.Split(parts[1], ";") {
+			if category = strings.TrimSpace(category); category != "" {
+				limit.categories = append(limit.categories, category)
+			}
+		}
+
+		if len(limit.categories) == 0 {
+			limit.categories = []string{""}
+		}
+
+		limits = append(limits, limit)
+	}
+
+	return limits
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which may be
+// either a number of seconds or an HTTP date, falling back on the default
+// backoff period if the header is missing or invalid.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return defaultRetryAfter
+	}
+
+	if seconds, err := strconv.ParseFloat(header, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second))
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return date.Sub(now)
+	}
+
+	return defaultRetryAfter
+}
@@ -0,0 +1,145 @@
+package sentry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimits(t *testing.T) {
+	dsn := "https://key@example.com/sentry/1"
+
+	t.Run("Until()", func(t *testing.T) {
+		r := rateLimits{}
+
+		_, limited := r.Until(dsn, rateLimitCategoryError)
+		assert.False(t, limited, "it should not be limited by default")
+
+		r.set(dsn, "transaction", time.Now().Add(time.Minute))
+		_, limited = r.Until(dsn, rateLimitCategoryError)
+		assert.False(t, limited, "it should not be limited by limits on other categories")
+
+		r.set("https://key@example.com/sentry/2", "", time.Now().Add(time.Minute))
+		_, limited = r.Until(dsn, rateLimitCategoryError)
+		assert.False(t, limited, "it should not be limited by limits on other DSNs")
+
+		r.set(dsn, rateLimitCategoryError, time.Now().Add(-time.Minute))
+		_, limited = r.Until(dsn, rateLimitCategoryError)
+		assert.False(t, limited, "it should not be limited by expired limits")
+
+		until := time.Now().Add(time.Minute)
+		r.set(dsn, rateLimitCategoryError, until)
+		u, limited := r.Until(dsn, rateLimitCategoryError)
+		assert.True(t, limited, "it should be limited by limits on its category")
+		assert.Equal(t, until, u, "it should report when the limit expires")
+
+		r.set(dsn, rateLimitCategoryError, time.Now())
+		u, _ = r.Until(dsn, rateLimitCategoryError)
+		assert.Equal(t, until, u, "it should not shorten an existing limit")
+
+		global := time.Now().Add(time.Hour)
+		r.set(dsn, "", global)
+		u, limited = r.Until(dsn, rateLimitCategoryError)
+		assert.True(t, limited, "it should be limited by limits on all categories")
+		assert.Equal(t, global, u, "it should report the latest expiry time")
+	})
+
+	t.Run("Update()", func(t *testing.T) {
+		cases := []struct {
+			Name       string
+			StatusCode int
+			Headers    map[string]string
+			Limited    bool
+			RetryAfter time.Duration
+		}{
+			{"200 OK", 200, map[string]string{}, false, 0},
+			{"429 without headers", 429, map[string]string{}, true, defaultRetryAfter},
+			{"429 with Retry-After", 429, map[string]string{"Retry-After": "30"}, true, 30 * time.Second},
+			{"429 with X-Sentry-Rate-Limits", 429, map[string]string{"Retry-After": "30", "X-Sentry-Rate-Limits": "120:error:key"}, true, 120 * time.Second},
+			{"429 with X-Sentry-Rate-Limits for other categories", 429, map[string]string{"X-Sentry-Rate-Limits": "120:transaction:key"}, false, 0},
+			{"200 with X-Sentry-Rate-Limits", 200, map[string]string{"X-Sentry-Rate-Limits": "60::organization"}, true, 60 * time.Second},
+		}
+
+		for _, tc := range cases {
+			tc := tc
+			t.Run(tc.Name, func(t *testing.T) {
+				r := rateLimits{}
+				res := &http.Response{
+					StatusCode: tc.StatusCode,
+					Header:     http.Header{},
+				}
+
+				for k, v := range tc.Headers {
+					res.Header.Set(k, v)
+				}
+
+				r.Update(dsn, res)
+
+				until, limited := r.Until(dsn, rateLimitCategoryError)
+				assert.Equal(t, tc.Limited, limited, "it should only be rate limited when the server requests it")
+				if tc.Limited {
+					assert.WithinDuration(t, time.Now().Add(tc.RetryAfter), until, time.Second, "it should back off for the right amount of time")
+				}
+			})
+		}
+	})
+
+	t.Run("parseRateLimits()", func(t *testing.T) {
+		assert.Equal(t, []rateLimit{}, parseRateLimits(""), "it should return no limits for an empty header")
+		assert.Equal(t, []rateLimit{}, parseRateLimits("invalid, x:error"), "it should ignore invalid entries")
+
+		assert.Equal(t, []rateLimit{
+			{retryAfter: 60 * time.Second, categories: []string{"error", "transaction"}},
+			{retryAfter: 2700 * time.Second, categories: []string{""}},
+			{retryAfter: 1500 * time.Millisecond, categories: []string{"default"}},
+		}, parseRateLimits("60:error;transaction:key, 2700::organization:quota_exceeded, 1.5:default"), "it should parse each of the limits")
+	})
+
+	t.Run("parseRetryAfter()", func(t *testing.T) {
+		now := time.Now()
+
+		assert.Equal(t, defaultRetryAfter, parseRetryAfter("", now), "it should use the default if no header is provided")
+		assert.Equal(t, defaultRetryAfter, parseRetryAfter("soon", now), "it should use the default if the header is invalid")
+		assert.Equal(t, 10*time.Second, parseRetryAfter("10", now), "it should support a number of seconds")
+
+		date := now.Add(2 * time.Minute).UTC().Truncate(time.Second)
+		assert.Equal(t, date.Sub(now), parseRetryAfter(date.Format(http.TimeFormat), now), "it should support an HTTP date")
+	})
+}
+
+// testTransportRateLimiting ensures that an HTTP based transport honours
+// the rate limits communicated to it by the Sentry server.
+func testTransportRateLimiting(t *testing.T, tr Transport) {
+	received := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		received++
+		res.Header().Set("Retry-After", "60")
+		res.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	uri, err := url.Parse(ts.URL)
+	require.Nil(t, err, "we should not fail to parse the URI")
+	uri.User = url.User("key")
+	uri.Path = "/1"
+
+	err = tr.Send(uri.String(), NewPacket())
+	assert.True(t, ErrRateLimited.IsInstance(err), "it should report that the event was rate limited")
+	assert.False(t, isRateLimitWindowError(err), "it should report that an attempt was made to send the first event")
+	assert.Equal(t, 1, received, "the server should have received the first event")
+
+	err = tr.Send(uri.String(), NewPacket())
+	assert.True(t, ErrRateLimited.IsInstance(err), "it should report that the event was rate limited")
+	assert.True(t, isRateLimitWindowError(err), "it should report that no attempt was made to send the event")
+	assert.Equal(t, 1, received, "the server should not receive events while we are backing off")
+
+	uri.Path = "/2"
+	err = tr.Send(uri.String(), NewPacket())
+	assert.True(t, ErrRateLimited.IsInstance(err), "it should report that the event was rate limited")
+	assert.Equal(t, 2, received, "the server should receive events for other DSNs")
+}
@@ -0,0 +1,150 @@
+package sentry
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// RecoverTimeout sets the maximum amount of time that RecoveringClient.Recover
+// will wait for a panic to be sent to Sentry before it returns (or re-panics).
+// A timeout of zero will cause it to return immediately.
+func RecoverTimeout(timeout time.Duration) Option {
+	return &recoverTimeoutOption{timeout}
+}
+
+// RecoverRepanic configures whether RecoveringClient.Recover will re-panic with the
+// original value once it has reported a panic to Sentry. This allows you
+// to report panics while still letting them crash your application.
+func RecoverRepanic(repanic bool) Option {
+	return &recoverRepanicOption{repanic}
+}
+
+func (c *client) Recover(options ...Option) {
+	// recover() only works when it is called directly by the deferred
+	// function, so this can't be moved into a helper.
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	cl := c.With(options...)
+	waitForPanic(cl, capturePanic(cl, r))
+
+	if shouldRepanic(cl) {
+		panic(r)
+	}
+}
+
+func (c *client) CapturePanic(recovered interface{}, options ...Option) QueuedEvent {
+	return c.Capture(append([]Option{panicException(recovered)}, options...)...)
+}
+
+// capturePanic reports a recovered panic using the client's CapturePanic
+// method if it is a RecoveringClient, or by capturing it as an exception
+// otherwise.
+func capturePanic(cl Client, recovered interface{}, options ...Option) QueuedEvent {
+	if rc, ok := cl.(RecoveringClient); ok {
+		return rc.CapturePanic(recovered, options...)
+	}
+
+	return cl.Capture(append([]Option{panicException(recovered)}, options...)...)
+}
+
+// waitForPanic waits for an event describing a panic to be sent, up to the
+// client's RecoverTimeout.
+func waitForPanic(cl Client, e QueuedEvent) {
+	timeout := 2 * time.Second
+	if opt, ok := cl.GetOption("sentry-go.recover.timeout").(*recoverTimeoutOption); ok {
+		timeout = opt.timeout
+	}
+
+	if timeout <= 0 {
+		return
+	}
+
+	select {
+	case <-e.WaitChannel():
+	case <-time.After(timeout):
+	}
+}
+
+// shouldRepanic determines whether a client has been configured to re-panic
+// once it has reported a panic.
+func shouldRepanic(cl Client) bool {
+	opt, ok := cl.GetOption("sentry-go.recover.repanic").(*recoverRepanicOption)
+	return ok && opt.repanic
+}
+
+// panicException builds an exception describing a recovered panic, using
+// the stack of the goroutine which panicked.
+func panicException(recovered interface{}) Option {
+	var ex *ExceptionInfo
+	opt := &exceptionOption{}
+
+	if err, ok := recovered.(error); ok {
+		opt = ExceptionForError(err).(*exceptionOption)
+		ex = opt.Exceptions[len(opt.Exceptions)-1]
+
+		if _, ok := err.(stackTracer); ok {
+			// The error knows where it was created, which is more useful
+			// than knowing where it was thrown from.
+			setPanicMechanism(ex)
+			return opt
+		}
+	} else {
+		ex = NewExceptionInfo()
+		ex.Type = reflect.TypeOf(recovered).String()
+		ex.Value = fmt.Sprint(recovered)
+		opt.Exceptions = append(opt.Exceptions, ex)
+	}
+
+	setPanicMechanism(ex)
+	frames, omitted := getPanicStacktraceFrames(0)
+	ex.StackTrace = &stackTraceOption{
+		Frames:  frames,
+		Omitted: omitted,
+
+		internalPrefixes:   append([]string{}, defaultInternalPrefixes...),
+		sourceContextLines: defaultSourceContextLines,
+	}
+
+	return opt
+}
+
+// setPanicMechanism marks an exception as having been caused by a panic,
+// keeping any details of the exception group it belongs to.
+func setPanicMechanism(ex *ExceptionInfo) {
+	m := NewExceptionMechanism("panic", false)
+	if ex.MechanismInfo != nil {
+		m.ExceptionID = ex.MechanismInfo.ExceptionID
+		m.ParentID = ex.MechanismInfo.ParentID
+		m.IsExceptionGroup = ex.MechanismInfo.IsExceptionGroup
+	}
+
+	ex.MechanismInfo = m
+}
+
+type recoverTimeoutOption struct {
+	timeout time.Duration
+}
+
+func (o *recoverTimeoutOption) Class() string {
+	return "sentry-go.recover.timeout"
+}
+
+func (o *recoverTimeoutOption) Omit() bool {
+	return true
+}
+
+type recoverRepanicOption struct {
+	repanic bool
+}
+
+func (o *recoverRepanicOption) Class() string {
+	return "sentry-go.recover.repanic"
+}
+
+func (o *recoverRepanicOption) Omit() bool {
+	return true
+}
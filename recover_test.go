@@ -0,0 +1,301 @@
+package sentry
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleRecoveringClient_Recover() {
+	cl := NewClient().(RecoveringClient)
+
+	func() {
+		// Any panics which occur within this function will be sent
+		// to Sentry before the function returns.
+		defer cl.Recover(
+			// You can wait for up to 5 seconds for the event to be sent
+			RecoverTimeout(5*time.Second),
+			// And re-panic once it has been sent if you want your
+			// application to crash as it normally would.
+			RecoverRepanic(false),
+		)
+
+		panic("something went wrong")
+	}()
+}
+
+func ExampleRecoveringClient_CapturePanic() {
+	cl := NewClient().(RecoveringClient)
+
+	defer func() {
+		if r := recover(); r != nil {
+			cl.CapturePanic(r, Tags(map[string]string{"recovered": "true"})).Wait()
+		}
+	}()
+
+	panic("something went wrong")
+}
+
+func TestRecover(t *testing.T) {
+	tr := &testFlakyTransport{}
+	q := NewSequentialSendQueue(10)
+	defer q.Shutdown(true)
+
+	cl := NewClient(UseTransport(tr), UseSendQueue(q)).(RecoveringClient)
+
+	lastPacket := func(t *testing.T) Packet {
+		tr.mutex.Lock()
+		defer tr.mutex.Unlock()
+
+		require.NotEmpty(t, tr.sent, "an event should have been sent")
+		return tr.sent[len(tr.sent)-1]
+	}
+
+	sentCount := func() int {
+		tr.mutex.Lock()
+		defer tr.mutex.Unlock()
+
+		return len(tr.sent)
+	}
+
+	t.Run("RecoverTimeout()", func(t *testing.T) {
+		o := RecoverTimeout(time.Second)
+		require.NotNil(t, o, "it should not return nil")
+		assert.Equal(t, "sentry-go.recover.timeout", o.Class(), "it should use the right option class")
+		if assert.Implements(t, (*OmitableOption)(nil), o, "it should implement the OmitableOption interface") {
+			assert.True(t, o.(OmitableOption).Omit(), "it should always return true for calls to Omit()")
+		}
+	})
+
+	t.Run("RecoverRepanic()", func(t *testing.T) {
+		o := RecoverRepanic(true)
+		require.NotNil(t, o, "it should not return nil")
+		assert.Equal(t, "sentry-go.recover.repanic", o.Class(), "it should use the right option class")
+		if assert.Implements(t, (*OmitableOption)(nil), o, "it should implement the OmitableOption interface") {
+			assert.True(t, o.(OmitableOption).Omit(), "it should always return true for calls to Omit()")
+		}
+	})
+
+	t.Run("Recover()", func(t *testing.T) {
+		t.Run("No Panic", func(t *testing.T) {
+			sent := sentCount()
+			func() {
+				defer cl.Recover()
+			}()
+
+			assert.Equal(t, sent, sentCount(), "no event should be sent if there was no panic")
+		})
+
+		t.Run("Panic", func(t *testing.T) {
+			assert.NotPanics(t, func() {
+				defer cl.Recover(Tags(map[string]string{"recovered": "true"}))
+				testPanickingFunction("test panic")
+			}, "it should recover the panic")
+
+			p := lastPacket(t)
+			assert.Equal(t, "true", p.Tags()["recovered"], "it should include the options which were provided")
+
+			exceptions := p.Exceptions()
+			require.Len(t, exceptions, 1, "it should include an exception")
+			assert.Equal(t, "string", exceptions[0].Type, "it should use the type of the recovered value")
+			assert.Equal(t, "test panic", exceptions[0].Value, "it should use the recovered value as the message")
+
+			if assert.NotNil(t, exceptions[0].MechanismInfo, "it should include the mechanism") {
+				assert.Equal(t, "panic", exceptions[0].MechanismInfo.Type, "it should use the panic mechanism")
+				if assert.NotNil(t, exceptions[0].MechanismInfo.Handled, "it should specify whether the panic was handled") {
+					assert.False(t, *exceptions[0].MechanismInfo.Handled, "it should mark the panic as unhandled")
+				}
+			}
+		})
+
+		t.Run("Repanic", func(t *testing.T) {
+			sent := sentCount()
+			assert.PanicsWithValue(t, "test panic", func() {
+				defer cl.Recover(RecoverRepanic(true))
+				panic("test panic")
+			}, "it should re-panic with the original value")
+
+			assert.Equal(t, sent+1, sentCount(), "it should send the event before re-panicking")
+		})
+
+		t.Run("Timeout", func(t *testing.T) {
+			tr := testNewBlockingTransport()
+			defer tr.Release()
+
+			q := NewSequentialSendQueue(10)
+			defer q.Shutdown(false)
+
+			cl := NewClient(UseTransport(tr), UseSendQueue(q)).(RecoveringClient)
+
+			start := time.Now()
+			func() {
+				defer cl.Recover(RecoverTimeout(10 * time.Millisecond))
+				panic("test panic")
+			}()
+
+			assert.True(t, time.Since(start) < time.Second, "it should stop waiting once the timeout has elapsed")
+		})
+	})
+
+	t.Run("CapturePanic()", func(t *testing.T) {
+		t.Run("Error", func(t *testing.T) {
+			var e QueuedEvent
+			func() {
+				defer func() {
+					e = cl.CapturePanic(recover())
+				}()
+
+				testPanickingFunction(fmt.Errorf("test error"))
+			}()
+
+			require.NotNil(t, e, "it should return the queued event")
+			assert.Nil(t, e.Error(), "the event should have been sent")
+
+			exceptions := lastPacket(t).Exceptions()
+			require.Len(t, exceptions, 1, "it should include an exception")
+			assert.Equal(t, "test error", exceptions[0].Value, "it should use the error's message")
+			if assert.NotNil(t, exceptions[0].MechanismInfo, "it should include the mechanism") {
+				assert.Equal(t, "panic", exceptions[0].MechanismInfo.Type, "it should use the panic mechanism")
+			}
+
+			st, ok := exceptions[0].StackTrace.(*stackTraceOption)
+			require.True(t, ok, "the stacktrace should be a *stackTraceOption")
+			require.NotEmpty(t, st.Frames, "the stacktrace should include frames")
+
+			frame := st.Frames[len(st.Frames)-1]
+			assert.Equal(t, "testPanickingFunction", frame.Function, "the stacktrace should start at the function which panicked")
+
+			for _, frame := range st.Frames {
+				assert.NotEqual(t, "gopanic", frame.Function, "the stacktrace should not include the runtime's panic handling")
+				assert.NotContains(t, frame.Function, "CapturePanic", "the stacktrace should not include the recovery handler")
+			}
+		})
+
+		t.Run("Error with StackTrace", func(t *testing.T) {
+			err := errors.New("test error")
+
+			var e QueuedEvent
+			func() {
+				defer func() {
+					e = cl.CapturePanic(recover())
+				}()
+
+				testPanickingFunction(err)
+			}()
+
+			require.NotNil(t, e, "it should return the queued event")
+			assert.Nil(t, e.Error(), "the event should have been sent")
+
+			exceptions := lastPacket(t).Exceptions()
+			require.NotEmpty(t, exceptions, "it should include an exception")
+
+			ex := exceptions[len(exceptions)-1]
+			if assert.NotNil(t, ex.MechanismInfo, "it should include the mechanism") {
+				assert.Equal(t, "panic", ex.MechanismInfo.Type, "it should use the panic mechanism")
+			}
+
+			st, ok := ex.StackTrace.(*stackTraceOption)
+			require.True(t, ok, "the stacktrace should be a *stackTraceOption")
+
+			// The packet's stacktrace has been finalized, so we only compare
+			// the locations of its frames with those of the error.
+			expected, _ := getStacktraceFramesForError(err)
+			require.Equal(t, expected.Len(), st.Frames.Len(), "it should use the stacktrace from the error")
+			for i, frame := range st.Frames {
+				assert.Equal(t, expected[i].Function, frame.Function, "it should use the stacktrace from the error (index=%d)", i)
+				assert.Equal(t, expected[i].Line, frame.Line, "it should use the stacktrace from the error (index=%d)", i)
+			}
+		})
+
+		t.Run("Error Group", func(t *testing.T) {
+			var e QueuedEvent
+			func() {
+				defer func() {
+					e = cl.CapturePanic(recover())
+				}()
+
+				testPanickingFunction(&testMultiError{[]error{fmt.Errorf("first"), fmt.Errorf("second")}})
+			}()
+
+			require.NotNil(t, e, "it should return the queued event")
+			assert.Nil(t, e.Error(), "the event should have been sent")
+
+			exceptions := lastPacket(t).Exceptions()
+			require.Len(t, exceptions, 3, "it should include every error in the group")
+
+			ex := exceptions[len(exceptions)-1]
+			if assert.NotNil(t, ex.MechanismInfo, "it should include the mechanism") {
+				assert.Equal(t, "panic", ex.MechanismInfo.Type, "it should use the panic mechanism")
+				assert.True(t, ex.MechanismInfo.IsExceptionGroup, "it should keep the exception group details")
+				if assert.NotNil(t, ex.MechanismInfo.ExceptionID, "it should keep the exception's ID") {
+					assert.Equal(t, 0, *ex.MechanismInfo.ExceptionID, "it should keep the exception's ID")
+				}
+			}
+		})
+
+		t.Run("Custom Client", func(t *testing.T) {
+			var e QueuedEvent
+			func() {
+				defer func() {
+					// Clients which don't implement RecoveringClient should
+					// still be able to report panics, like the HTTP handler does.
+					e = capturePanic(&struct{ Client }{cl}, recover())
+				}()
+
+				testPanickingFunction(fmt.Errorf("test error"))
+			}()
+
+			require.NotNil(t, e, "it should return the queued event")
+			assert.Nil(t, e.Error(), "the event should have been sent")
+
+			exceptions := lastPacket(t).Exceptions()
+			require.Len(t, exceptions, 1, "it should include an exception")
+			if assert.NotNil(t, exceptions[0].MechanismInfo, "it should include the mechanism") {
+				assert.Equal(t, "panic", exceptions[0].MechanismInfo.Type, "it should use the panic mechanism")
+			}
+
+			st, ok := exceptions[0].StackTrace.(*stackTraceOption)
+			require.True(t, ok, "the stacktrace should be a *stackTraceOption")
+			require.NotEmpty(t, st.Frames, "the stacktrace should include frames")
+			assert.Equal(t, "testPanickingFunction", st.Frames[len(st.Frames)-1].Function, "the stacktrace should start at the function which panicked")
+		})
+	})
+}
+
+func TestExceptionMechanism(t *testing.T) {
+	m := NewExceptionMechanism("panic", false)
+	require.NotNil(t, m, "it should not return nil")
+	assert.Equal(t, "panic", m.Type, "it should set the mechanism type")
+	if assert.NotNil(t, m.Handled, "it should set whether the exception was handled") {
+		assert.False(t, *m.Handled, "it should set whether the exception was handled")
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"values": []interface{}{
+			map[string]interface{}{
+				"type":  "TestException",
+				"value": "This is a test",
+				"mechanism": map[string]interface{}{
+					"type":    "panic",
+					"handled": false,
+				},
+			},
+		},
+	}, testOptionsSerialize(t, Exception(&ExceptionInfo{
+		Type:          "TestException",
+		Value:         "This is a test",
+		MechanismInfo: m,
+	})), "it should serialize the mechanism")
+}
+
+// testPanickingFunction panics with the provided value, giving tests a
+// known function to look for in stack traces.
+//
+//go:noinline
+func testPanickingFunction(value interface{}) {
+	panic(value)
+}
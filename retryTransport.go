@@ -0,0 +1,155 @@
+package sentry
+
+import (
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// A RetryTransport wraps another Transport and retries sending events
+// which fail as a result of transient network or server errors, backing
+// off exponentially between attempts.
+type RetryTransport interface {
+	Transport
+
+	// WithMaxAttempts sets the maximum number of attempts which will be
+	// made to send an event, including the first.
+	WithMaxAttempts(attempts int) RetryTransport
+
+	// WithBackoff configures the delay before the first retry and the
+	// maximum delay between any two attempts. The delay doubles after
+	// each failed attempt and is randomly jittered to avoid retry storms.
+	WithBackoff(initial, max time.Duration) RetryTransport
+
+	// WithDeadline limits the total amount of time which may be spent
+	// attempting to send an event. No new attempts will be started once
+	// the deadline would be exceeded.
+	WithDeadline(deadline time.Duration) RetryTransport
+}
+
+// NewRetryTransport creates a new RetryTransport which uses the provided
+// transport to send events, retrying them if they fail with a network
+// error or a 5xx response from the server.
+// Keep in mind that the SendQueue will wait for all retries to complete
+// before it moves on to the next event.
+func NewRetryTransport(transport Transport) RetryTransport {
+	return &retryTransport{
+		transport:      transport,
+		maxAttempts:    3,
+		initialBackoff: time.Second,
+		maxBackoff:     30 * time.Second,
+		deadline:       time.Minute,
+	}
+}
+
+// attemptCountingTransport is implemented by transports which are able to
+// report the number of attempts they made to send an event.
+type attemptCountingTransport interface {
+	sendCountingAttempts(dsn string, packet Packet) (int, error)
+}
+
+type retryTransport struct {
+	transport Transport
+
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	deadline       time.Duration
+}
+
+func (t *retryTransport) WithMaxAttempts(attempts int) RetryTransport {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	t.maxAttempts = attempts
+	return t
+}
+
+func (t *retryTransport) WithBackoff(initial, max time.Duration) RetryTransport {
+	if max < initial {
+		max = initial
+	}
+
+	t.initialBackoff = initial
+	t.maxBackoff = max
+	return t
+}
+
+func (t *retryTransport) WithDeadline(deadline time.Duration) RetryTransport {
+	t.deadline = deadline
+	return t
+}
+
+func (t *retryTransport) Send(dsn string, packet Packet) error {
+	_, err := t.sendCountingAttempts(dsn, packet)
+	return err
+}
+
+func (t *retryTransport) sendCountingAttempts(dsn string, packet Packet) (int, error) {
+	started := time.Now()
+
+	attempts := 0
+	for {
+		attempts++
+
+		err := t.transport.Send(dsn, packet)
+		if isRateLimitWindowError(err) {
+			// The transport didn't make an attempt to send the event.
+			return attempts - 1, err
+		}
+
+		if err == nil || !isRetryableError(err) {
+			return attempts, err
+		}
+
+		if attempts >= t.maxAttempts {
+			return attempts, errors.Wrapf(err, "giving up after %d attempts", attempts)
+		}
+
+		delay := t.backoff(attempts)
+		if t.deadline > 0 && time.Since(started)+delay > t.deadline {
+			return attempts, errors.Wrapf(err, "giving up after %d attempts, deadline exceeded", attempts)
+		}
+
+		time.Sleep(delay)
+	}
+}
+
+// backoff determines how long we should wait after the given number of
+// failed attempts, picking a random delay between half and the full
+// exponential backoff period.
+func (t *retryTransport) backoff(attempts int) time.Duration {
+	delay := t.initialBackoff
+	for i := 1; i < attempts && delay < t.maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > t.maxBackoff {
+		delay = t.maxBackoff
+	}
+
+	if delay <= 1 {
+		return delay
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+}
+
+// isRetryableError determines whether an error returned by a transport
+// is likely to be transient, in which case it is worth retrying.
+func isRetryableError(err error) bool {
+	if ErrRateLimited.IsInstance(err) {
+		return false
+	}
+
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
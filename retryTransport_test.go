@@ -0,0 +1,196 @@
+package sentry
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewRetryTransport() {
+	cl := NewClient(
+		// You can wrap any transport to have it retry events which fail
+		// to send due to network issues or server errors
+		UseTransport(
+			NewRetryTransport(NewEnvelopeTransport()).
+				WithMaxAttempts(5).
+				WithBackoff(500*time.Millisecond, 10*time.Second).
+				WithDeadline(time.Minute),
+		),
+	)
+
+	e := cl.Capture(Message("Sent with retries"))
+
+	// You can find out how many attempts were needed to send the event
+	if ae, ok := e.(AttemptCountingEvent); ok {
+		fmt.Printf("Sent event in %d attempts\n", ae.Attempts())
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	tr := NewRetryTransport(&testFlakyTransport{})
+	require.NotNil(t, tr, "the transport should not be nil")
+	assert.Implements(t, (*Transport)(nil), tr, "it should implement the Transport interface")
+
+	rt, ok := tr.(*retryTransport)
+	require.True(t, ok, "it should actually be a *retryTransport")
+
+	t.Run("WithMaxAttempts()", func(t *testing.T) {
+		assert.Equal(t, tr, tr.WithMaxAttempts(5), "it should return the transport for chaining")
+		assert.Equal(t, 5, rt.maxAttempts, "it should set the maximum number of attempts")
+
+		tr.WithMaxAttempts(0)
+		assert.Equal(t, 1, rt.maxAttempts, "it should always make at least one attempt")
+	})
+
+	t.Run("WithBackoff()", func(t *testing.T) {
+		assert.Equal(t, tr, tr.WithBackoff(time.Second, time.Minute), "it should return the transport for chaining")
+		assert.Equal(t, time.Second, rt.initialBackoff, "it should set the initial backoff")
+		assert.Equal(t, time.Minute, rt.maxBackoff, "it should set the maximum backoff")
+
+		tr.WithBackoff(time.Second, time.Millisecond)
+		assert.Equal(t, time.Second, rt.maxBackoff, "the maximum backoff should never be less than the initial backoff")
+	})
+
+	t.Run("WithDeadline()", func(t *testing.T) {
+		assert.Equal(t, tr, tr.WithDeadline(time.Minute), "it should return the transport for chaining")
+		assert.Equal(t, time.Minute, rt.deadline, "it should set the deadline")
+	})
+
+	t.Run("backoff()", func(t *testing.T) {
+		rt := NewRetryTransport(nil).WithBackoff(100*time.Millisecond, time.Second).(*retryTransport)
+
+		cases := []struct {
+			Attempts int
+			Max      time.Duration
+		}{
+			{1, 100 * time.Millisecond},
+			{2, 200 * time.Millisecond},
+			{3, 400 * time.Millisecond},
+			{4, 800 * time.Millisecond},
+			{5, time.Second},
+			{100, time.Second},
+		}
+
+		for _, tc := range cases {
+			for i := 0; i < 10; i++ {
+				delay := rt.backoff(tc.Attempts)
+				assert.True(t, delay >= tc.Max/2 && delay <= tc.Max, "the delay after %d attempts should be between %s and %s, got %s", tc.Attempts, tc.Max/2, tc.Max, delay)
+			}
+		}
+
+		assert.Equal(t, time.Duration(0), NewRetryTransport(nil).WithBackoff(0, 0).(*retryTransport).backoff(1), "it should support disabling the backoff")
+	})
+
+	t.Run("Send()", func(t *testing.T) {
+		transientErr := errors.Wrap(&net.DNSError{Err: "temporary failure", IsTemporary: true}, "failed to submit request")
+
+		cases := []struct {
+			Name        string
+			Failures    int
+			Err         error
+			MaxAttempts int
+			Deadline    time.Duration
+			Attempts    int
+			Success     bool
+		}{
+			{"Success", 0, nil, 3, time.Minute, 1, true},
+			{"Transient Network Error", 2, transientErr, 3, time.Minute, 3, true},
+			{"Server Error", 1, &httpStatusError{503}, 3, time.Minute, 2, true},
+			{"Client Error", 1, &httpStatusError{400}, 3, time.Minute, 1, false},
+			{"Rate Limited", 1, errors.Wrap(&httpStatusError{429}, ErrRateLimited.Error()), 3, time.Minute, 1, false},
+			{"Unknown Error", 1, fmt.Errorf("test error"), 3, time.Minute, 1, false},
+			{"Attempts Exhausted", 5, &httpStatusError{500}, 3, time.Minute, 3, false},
+			{"Deadline Exceeded", 5, &httpStatusError{500}, 10, 5 * time.Millisecond, 1, false},
+		}
+
+		for _, tc := range cases {
+			tc := tc
+			t.Run(tc.Name, func(t *testing.T) {
+				ft := &testFlakyTransport{failures: tc.Failures, err: tc.Err}
+				tr := NewRetryTransport(ft).
+					WithMaxAttempts(tc.MaxAttempts).
+					WithBackoff(10*time.Millisecond, 20*time.Millisecond).
+					WithDeadline(tc.Deadline)
+
+				attempts, err := tr.(*retryTransport).sendCountingAttempts("", NewPacket())
+				assert.Equal(t, tc.Attempts, attempts, "it should report the number of attempts made")
+				assert.Equal(t, tc.Attempts, ft.calls, "it should make the right number of attempts")
+				if tc.Success {
+					assert.Nil(t, err, "it should not return an error")
+				} else {
+					assert.NotNil(t, err, "it should return an error")
+					assert.Contains(t, err.Error(), tc.Err.Error(), "it should return the last error")
+				}
+
+				ft.failures = tc.Failures
+				ft.calls = 0
+				assert.Equal(t, err == nil, tr.Send("", NewPacket()) == nil, "Send() should behave in the same way")
+			})
+		}
+
+		t.Run("Rate Limit Window", func(t *testing.T) {
+			ft := &testFlakyTransport{failures: 1, err: errors.Wrap(&rateLimitWindowError{time.Now().Add(time.Minute)}, ErrRateLimited.Error())}
+			tr := NewRetryTransport(ft).WithBackoff(0, 0)
+
+			attempts, err := tr.(*retryTransport).sendCountingAttempts("", NewPacket())
+			assert.True(t, ErrRateLimited.IsInstance(err), "it should return the rate limit error")
+			assert.Equal(t, 1, ft.calls, "it should not retry the event")
+			assert.Equal(t, 0, attempts, "it should not count events which were not sent as attempts")
+		})
+	})
+
+	t.Run("SendQueue", func(t *testing.T) {
+		q := NewSequentialSendQueue(10)
+		defer q.Shutdown(true)
+
+		ft := &testFlakyTransport{failures: 2, err: &httpStatusError{502}}
+		cl := NewClient(
+			UseSendQueue(q),
+			UseTransport(NewRetryTransport(ft).WithBackoff(time.Millisecond, time.Millisecond)),
+		)
+
+		e := cl.Capture(Message("test"))
+		assert.Nil(t, e.Error(), "the event should have been sent")
+		assert.Equal(t, 3, e.(AttemptCountingEvent).Attempts(), "the event should report the number of attempts made")
+	})
+
+	t.Run("isRetryableError()", func(t *testing.T) {
+		assert.True(t, isRetryableError(&httpStatusError{500}), "server errors should be retried")
+		assert.True(t, isRetryableError(errors.Wrap(&httpStatusError{503}, "test")), "wrapped server errors should be retried")
+		assert.False(t, isRetryableError(&httpStatusError{401}), "client errors should not be retried")
+		assert.False(t, isRetryableError(errors.Wrap(&httpStatusError{429}, ErrRateLimited.Error())), "rate limited events should not be retried")
+		assert.True(t, isRetryableError(errors.Wrap(&net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}, "failed to submit request")), "network errors should be retried")
+		assert.False(t, isRetryableError(ErrBadURL), "other errors should not be retried")
+	})
+}
+
+// testFlakyTransport fails to send the first few events it receives
+// with the configured error.
+type testFlakyTransport struct {
+	failures int
+	err      error
+	calls    int
+	sent     []Packet
+
+	mutex sync.Mutex
+}
+
+func (t *testFlakyTransport) Send(dsn string, packet Packet) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.calls++
+	t.sent = append(t.sent, packet)
+	if t.failures > 0 {
+		t.failures--
+		return t.err
+	}
+
+	return nil
+}
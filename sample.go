@@ -0,0 +1,65 @@
+package sentry
+
+import "math/rand"
+
+// ErrEventSampled is used when an event is not sent to Sentry because it
+// was discarded by the client's SampleRate or Sampler.
+const ErrEventSampled = ErrType("sentry: event was sampled out")
+
+// SampleRate allows you to send only a fraction of the events captured by
+// a client to Sentry. It should be a value between 0.0 (send nothing) and
+// 1.0 (send everything), with events which are not sampled failing with
+// ErrEventSampled.
+func SampleRate(rate float64) Option {
+	return Sampler(func(packet Packet) float64 {
+		return rate
+	})
+}
+
+// Sampler allows you to dynamically determine the rate at which events
+// should be sent to Sentry, based on their content. The sampler is called
+// with every event captured by a client and should return a value between
+// 0.0 (drop the event) and 1.0 (always send the event).
+//
+// It may be provided to a client or passed to Capture() for a single event,
+// in which case it replaces the client's sampler.
+func Sampler(sampler func(packet Packet) float64) Option {
+	if sampler == nil {
+		return nil
+	}
+
+	return &samplerOption{sampler}
+}
+
+type samplerOption struct {
+	sampler func(packet Packet) float64
+}
+
+func (o *samplerOption) Class() string {
+	return "sentry-go.sampler"
+}
+
+func (o *samplerOption) Omit() bool {
+	return true
+}
+
+// sampled determines whether a packet should be sent to Sentry.
+func (o *samplerOption) sampled(p Packet) bool {
+	rate := o.sampler(p)
+	if rate >= 1.0 {
+		return true
+	}
+
+	return rand.Float64() < rate
+}
+
+// applySampler determines whether the event's Sampler, or the client's if
+// none was provided for the event, has chosen to send a packet to Sentry.
+func (c *client) applySampler(p Packet, options []Option) bool {
+	opt, ok := c.getEventOption("sentry-go.sampler", options).(*samplerOption)
+	if !ok {
+		return true
+	}
+
+	return opt.sampled(p)
+}
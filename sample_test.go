@@ -0,0 +1,122 @@
+package sentry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleSampleRate() {
+	cl := NewClient(
+		// You can choose to only send 10% of your events to Sentry
+		SampleRate(0.1),
+	)
+
+	e := cl.Capture(Message("This event might not be sent"))
+
+	if ErrEventSampled.IsInstance(e.Error()) {
+		// The event was not selected by the sampler
+	}
+}
+
+func ExampleSampler() {
+	cl := NewClient(
+		// Or decide how frequently to send events based on their content
+		Sampler(func(packet Packet) float64 {
+			if packet.Level() == Error {
+				return 1.0
+			}
+
+			return 0.1
+		}),
+	)
+
+	cl.Capture(Message("This warning might not be sent"), Level(Warning))
+}
+
+func TestSampler(t *testing.T) {
+	assert.Nil(t, Sampler(nil), "it should return nil if the sampler is nil")
+
+	o := Sampler(func(p Packet) float64 { return 0.5 })
+	require.NotNil(t, o, "it should not return nil if the sampler is non-nil")
+	assert.Implements(t, (*Option)(nil), o, "it should implement the Option interface")
+	assert.Equal(t, "sentry-go.sampler", o.Class(), "it should use the right option class")
+
+	if assert.Implements(t, (*OmitableOption)(nil), o, "it should implement the OmitableOption interface") {
+		assert.True(t, o.(OmitableOption).Omit(), "it should always return true for calls to Omit()")
+	}
+
+	t.Run("sampled()", func(t *testing.T) {
+		count := func(o Option) int {
+			sampled := 0
+			for i := 0; i < 1000; i++ {
+				if o.(*samplerOption).sampled(NewPacket()) {
+					sampled++
+				}
+			}
+
+			return sampled
+		}
+
+		assert.Equal(t, 1000, count(SampleRate(1.0)), "it should send every event with a rate of 1.0")
+		assert.Equal(t, 1000, count(SampleRate(2.0)), "it should send every event with a rate above 1.0")
+		assert.Equal(t, 0, count(SampleRate(0.0)), "it should send no events with a rate of 0.0")
+		assert.Equal(t, 0, count(SampleRate(-1.0)), "it should send no events with a negative rate")
+
+		sampled := count(SampleRate(0.5))
+		assert.True(t, sampled > 350 && sampled < 650, "it should send roughly half of the events with a rate of 0.5, sent %d", sampled)
+
+		var received Packet
+		o := Sampler(func(p Packet) float64 {
+			received = p
+			return 1.0
+		})
+
+		p := NewPacket()
+		assert.True(t, o.(*samplerOption).sampled(p), "it should send the event")
+		assert.Equal(t, p, received, "it should call the sampler with the packet")
+	})
+
+	t.Run("Capture()", func(t *testing.T) {
+		tr := testNewTestTransport()
+		q := NewSequentialSendQueue(10)
+		defer q.Shutdown(true)
+
+		cl := NewClient(UseTransport(tr), UseSendQueue(q))
+
+		t.Run("Sampled", func(t *testing.T) {
+			e := cl.With(SampleRate(1.0)).Capture(Message("test"))
+			<-tr.ch
+			assert.Nil(t, e.Error(), "the event should have been sent")
+		})
+
+		t.Run("Not Sampled", func(t *testing.T) {
+			e := cl.With(SampleRate(0.0)).Capture(Message("test"))
+			require.NotNil(t, e, "the event should not be nil")
+			assert.NotEmpty(t, e.EventID(), "the event should still have an ID")
+			assert.True(t, ErrEventSampled.IsInstance(e.Error()), "the event should fail with ErrEventSampled")
+			assert.False(t, ErrEventDropped.IsInstance(e.Error()), "the event should not be reported as dropped")
+		})
+
+		t.Run("Before BeforeSend", func(t *testing.T) {
+			called := false
+			e := cl.With(SampleRate(0.0), BeforeSend(func(p Packet) Packet {
+				called = true
+				return p
+			})).Capture(Message("test"))
+
+			assert.True(t, ErrEventSampled.IsInstance(e.Error()), "the event should fail with ErrEventSampled")
+			assert.False(t, called, "the BeforeSend hook should not be called for events which were not sampled")
+		})
+
+		t.Run("Event Sampler", func(t *testing.T) {
+			e := cl.With(SampleRate(1.0)).Capture(Message("test"), SampleRate(0.0))
+			assert.True(t, ErrEventSampled.IsInstance(e.Error()), "the event's sampler should replace the client's")
+
+			e = cl.With(SampleRate(0.0)).Capture(Message("test"), SampleRate(1.0))
+			<-tr.ch
+			assert.Nil(t, e.Error(), "the event's sampler should replace the client's")
+		})
+	})
+}
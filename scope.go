@@ -0,0 +1,252 @@
+package sentry
+
+import "sync"
+
+// A Scope holds mutable state, like the current user, tags and breadcrumbs,
+// which should be included in events. Unlike the options attached to a
+// client using With(), a scope can be modified after it has been created
+// and will apply its current state to every event it is included in.
+//
+// A Scope is itself an Option, so you can include it in events directly,
+// attach it to a client using With(), or manage a stack of them with a Hub.
+// Options provided when capturing an event take priority over the scope's.
+type Scope interface {
+	Option
+
+	// SetTag sets the value of a tag which will be included in events.
+	SetTag(key, value string) Scope
+
+	// SetTags sets the values of multiple tags at once.
+	SetTags(tags map[string]string) Scope
+
+	// RemoveTag removes a tag from the scope.
+	RemoveTag(key string) Scope
+
+	// SetExtra sets a piece of extra information which will be included
+	// in events.
+	SetExtra(key string, value interface{}) Scope
+
+	// RemoveExtra removes a piece of extra information from the scope.
+	RemoveExtra(key string) Scope
+
+	// SetUser sets the user which will be included in events, or clears
+	// it if user is nil.
+	SetUser(user *UserInfo) Scope
+
+	// SetLevel sets the severity level of events, or clears it if the
+	// level is empty.
+	SetLevel(level Severity) Scope
+
+	// SetFingerprint sets the fingerprint used to group events, or clears
+	// it if no keys are provided.
+	SetFingerprint(keys ...string) Scope
+
+	// Breadcrumbs returns the list of breadcrumbs which are kept by this
+	// scope. Once it has been used, these breadcrumbs will be included
+	// in events instead of the client's.
+	Breadcrumbs() BreadcrumbsList
+
+	// Clear removes all of the state held by the scope.
+	Clear() Scope
+
+	// Clone creates a copy of this scope which can then be modified
+	// independently.
+	Clone() Scope
+}
+
+// NewScope creates a new, empty, Scope.
+func NewScope() Scope {
+	return &scope{
+		tags:  map[string]string{},
+		extra: map[string]interface{}{},
+	}
+}
+
+type scope struct {
+	tags        map[string]string
+	extra       map[string]interface{}
+	user        *UserInfo
+	level       Severity
+	fingerprint []string
+	breadcrumbs *breadcrumbsList
+
+	mutex sync.RWMutex
+}
+
+func (s *scope) Class() string {
+	return "sentry-go.scope"
+}
+
+func (s *scope) SetTag(key, value string) Scope {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tags[key] = value
+	return s
+}
+
+func (s *scope) SetTags(tags map[string]string) Scope {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for k, v := range tags {
+		s.tags[k] = v
+	}
+
+	return s
+}
+
+func (s *scope) RemoveTag(key string) Scope {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.tags, key)
+	return s
+}
+
+func (s *scope) SetExtra(key string, value interface{}) Scope {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.extra[key] = value
+	return s
+}
+
+func (s *scope) RemoveExtra(key string) Scope {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.extra, key)
+	return s
+}
+
+func (s *scope) SetUser(user *UserInfo) Scope {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if user == nil {
+		s.user = nil
+		return s
+	}
+
+	u := *user
+	s.user = &u
+	return s
+}
+
+func (s *scope) SetLevel(level Severity) Scope {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.level = level
+	return s
+}
+
+func (s *scope) SetFingerprint(keys ...string) Scope {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.fingerprint = append([]string{}, keys...)
+	return s
+}
+
+func (s *scope) Breadcrumbs() BreadcrumbsList {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.breadcrumbs == nil {
+		s.breadcrumbs = NewBreadcrumbsList(10).(*breadcrumbsList)
+	}
+
+	return s.breadcrumbs
+}
+
+func (s *scope) Clear() Scope {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tags = map[string]string{}
+	s.extra = map[string]interface{}{}
+	s.user = nil
+	s.level = ""
+	s.fingerprint = nil
+	s.breadcrumbs = nil
+	return s
+}
+
+func (s *scope) Clone() Scope {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ns := &scope{
+		tags:        make(map[string]string, len(s.tags)),
+		extra:       make(map[string]interface{}, len(s.extra)),
+		user:        s.user,
+		level:       s.level,
+		fingerprint: s.fingerprint,
+	}
+
+	for k, v := range s.tags {
+		ns.tags[k] = v
+	}
+
+	for k, v := range s.extra {
+		ns.extra[k] = v
+	}
+
+	if s.breadcrumbs != nil {
+		ns.breadcrumbs = s.breadcrumbs.clone()
+	}
+
+	return ns
+}
+
+func (s *scope) Apply(p map[string]Option) {
+	for _, opt := range s.options() {
+		packet(p).setOption(opt)
+	}
+}
+
+// options builds the options which describe the current state of the scope.
+func (s *scope) options() []Option {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	opts := []Option{}
+
+	if len(s.tags) > 0 {
+		tags := make(map[string]string, len(s.tags))
+		for k, v := range s.tags {
+			tags[k] = v
+		}
+
+		opts = append(opts, Tags(tags))
+	}
+
+	if len(s.extra) > 0 {
+		extra := make(map[string]interface{}, len(s.extra))
+		for k, v := range s.extra {
+			extra[k] = v
+		}
+
+		opts = append(opts, Extra(extra))
+	}
+
+	if s.user != nil {
+		opts = append(opts, User(s.user))
+	}
+
+	if s.level != "" {
+		opts = append(opts, Level(s.level))
+	}
+
+	if len(s.fingerprint) > 0 {
+		opts = append(opts, Fingerprint(s.fingerprint...))
+	}
+
+	if s.breadcrumbs != nil {
+		opts = append(opts, Breadcrumbs(s.breadcrumbs))
+	}
+
+	return opts
+}
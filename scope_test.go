@@ -0,0 +1,188 @@
+package sentry
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewScope() {
+	cl := NewClient()
+
+	scope := NewScope().
+		SetTag("request_id", "0e2a1d0c").
+		SetUser(&UserInfo{ID: "1234"})
+
+	// You can attach a scope to a client, and any changes made to it
+	// will be included in events captured by that client
+	cl = cl.With(scope)
+
+	scope.SetLevel(Warning)
+	scope.Breadcrumbs().NewDefault(nil).WithMessage("Loaded user's profile")
+
+	cl.Capture(Message("This event includes the request ID, user and breadcrumb"))
+}
+
+func TestScope(t *testing.T) {
+	s := NewScope()
+	require.NotNil(t, s, "it should not return nil")
+	assert.Implements(t, (*Option)(nil), s, "it should implement the Option interface")
+	assert.Implements(t, (*AdvancedOption)(nil), s, "it should implement the AdvancedOption interface")
+	assert.Equal(t, "sentry-go.scope", s.Class(), "it should use the right option class")
+
+	serialize := func(t *testing.T, options ...Option) map[string]interface{} {
+		data, ok := testSerializePacket(t, NewPacket().SetOptions(options...)).(map[string]interface{})
+		require.True(t, ok, "the packet should serialize to an object")
+		return data
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		assert.Equal(t, map[string]interface{}{}, serialize(t, NewScope()), "it should not add anything to the packet")
+	})
+
+	t.Run("SetTag()", func(t *testing.T) {
+		s := NewScope()
+		assert.Equal(t, s, s.SetTag("a", "1"), "it should return the scope for chaining")
+		assert.Equal(t, map[string]interface{}{"a": "1"}, serialize(t, s)["tags"], "it should add the tag")
+
+		assert.Equal(t, s, s.SetTags(map[string]string{"b": "2", "c": "3"}), "it should return the scope for chaining")
+		assert.Equal(t, map[string]interface{}{"a": "1", "b": "2", "c": "3"}, serialize(t, s)["tags"], "it should add the tags")
+
+		assert.Equal(t, s, s.RemoveTag("b"), "it should return the scope for chaining")
+		assert.Equal(t, map[string]interface{}{"a": "1", "c": "3"}, serialize(t, s)["tags"], "it should remove the tag")
+
+		assert.Equal(t, map[string]interface{}{"a": "1", "c": "4", "d": "5"}, serialize(t,
+			Tags(map[string]string{"c": "2", "d": "5"}),
+			s,
+			Tags(map[string]string{"c": "4"}),
+		)["tags"], "it should merge the tags with the packet's")
+	})
+
+	t.Run("SetExtra()", func(t *testing.T) {
+		s := NewScope()
+		assert.Equal(t, s, s.SetExtra("a", 1), "it should return the scope for chaining")
+		assert.Equal(t, map[string]interface{}{"a": 1.0}, serialize(t, s)["extra"], "it should add the extra information")
+
+		assert.Equal(t, s, s.RemoveExtra("a"), "it should return the scope for chaining")
+		assert.NotContains(t, serialize(t, s), "extra", "it should remove the extra information")
+	})
+
+	t.Run("SetUser()", func(t *testing.T) {
+		s := NewScope()
+		user := &UserInfo{ID: "1234"}
+		assert.Equal(t, s, s.SetUser(user), "it should return the scope for chaining")
+
+		user.ID = "5678"
+		assert.Equal(t, map[string]interface{}{"id": "1234"}, serialize(t, s)["user"], "it should add a copy of the user")
+
+		assert.Equal(t, s, s.SetUser(nil), "it should return the scope for chaining")
+		assert.NotContains(t, serialize(t, s), "user", "it should remove the user")
+	})
+
+	t.Run("SetLevel()", func(t *testing.T) {
+		s := NewScope()
+		assert.Equal(t, s, s.SetLevel(Warning), "it should return the scope for chaining")
+		assert.Equal(t, Warning, NewPacket().SetOptions(Level(Error), s).Level(), "it should set the level")
+		assert.Equal(t, Fatal, NewPacket().SetOptions(s, Level(Fatal)).Level(), "it should be overridden by later options")
+
+		s.SetLevel("")
+		assert.Equal(t, Error, NewPacket().SetOptions(Level(Error), s).Level(), "it should clear the level")
+	})
+
+	t.Run("SetFingerprint()", func(t *testing.T) {
+		s := NewScope()
+		assert.Equal(t, s, s.SetFingerprint("a", "b"), "it should return the scope for chaining")
+		assert.Equal(t, []interface{}{"a", "b"}, serialize(t, s)["fingerprint"], "it should set the fingerprint")
+
+		s.SetFingerprint()
+		assert.NotContains(t, serialize(t, s), "fingerprint", "it should clear the fingerprint")
+	})
+
+	t.Run("Breadcrumbs()", func(t *testing.T) {
+		s := NewScope()
+		assert.NotContains(t, serialize(t, Breadcrumbs(DefaultBreadcrumbs()), s)["breadcrumbs"], "it should not replace the packet's breadcrumbs until used")
+
+		b := s.Breadcrumbs()
+		require.NotNil(t, b, "it should return a breadcrumbs list")
+		assert.Equal(t, b, s.Breadcrumbs(), "it should return the same list each time")
+
+		b.NewDefault(nil).WithMessage("test")
+		crumbs, ok := serialize(t, Breadcrumbs(DefaultBreadcrumbs()), s)["breadcrumbs"].([]interface{})
+		require.True(t, ok, "the packet should include the scope's breadcrumbs")
+		require.Len(t, crumbs, 1, "it should replace the packet's breadcrumbs")
+		assert.Equal(t, "test", crumbs[0].(map[string]interface{})["message"], "it should include the scope's breadcrumb")
+	})
+
+	t.Run("Clear()", func(t *testing.T) {
+		s := NewScope().
+			SetTag("a", "1").
+			SetExtra("b", 2).
+			SetUser(&UserInfo{ID: "1234"}).
+			SetLevel(Warning).
+			SetFingerprint("c")
+		s.Breadcrumbs().NewDefault(nil)
+
+		assert.Equal(t, s, s.Clear(), "it should return the scope for chaining")
+		assert.Equal(t, map[string]interface{}{}, serialize(t, s), "it should clear all of the scope's state")
+	})
+
+	t.Run("Clone()", func(t *testing.T) {
+		s := NewScope().SetTag("a", "1").SetExtra("b", 2)
+		s.Breadcrumbs().NewDefault(nil).WithMessage("parent")
+
+		c := s.Clone()
+		require.NotNil(t, c, "it should not return nil")
+		assert.False(t, s == c, "it should return a new scope")
+		assert.Equal(t, serialize(t, s), serialize(t, c), "it should copy the scope's state")
+
+		c.SetTag("a", "2").SetExtra("b", 3)
+		c.Breadcrumbs().NewDefault(nil).WithMessage("child")
+
+		assert.Equal(t, map[string]interface{}{"a": "1"}, serialize(t, s)["tags"], "it should not modify the original's tags")
+		assert.Equal(t, map[string]interface{}{"b": 2.0}, serialize(t, s)["extra"], "it should not modify the original's extra information")
+		assert.Len(t, serialize(t, s)["breadcrumbs"], 1, "it should not modify the original's breadcrumbs")
+		assert.Len(t, serialize(t, c)["breadcrumbs"], 2, "it should copy the original's breadcrumbs")
+	})
+
+	t.Run("Capture()", func(t *testing.T) {
+		tr := &testFlakyTransport{}
+		q := NewSequentialSendQueue(10)
+		defer q.Shutdown(true)
+
+		s := NewScope().SetTag("a", "1")
+		cl := NewClient(UseTransport(tr), UseSendQueue(q), Tags(map[string]string{"b": "2"})).With(s)
+
+		s.SetTag("c", "3")
+		assert.Nil(t, cl.Capture(Message("test")).Error(), "the event should have been sent")
+
+		tr.mutex.Lock()
+		defer tr.mutex.Unlock()
+
+		require.Len(t, tr.sent, 1, "the event should have been sent")
+		assert.Equal(t, map[string]string{"a": "1", "b": "2", "c": "3"}, tr.sent[0].Tags(), "it should include the scope's state when the event is captured")
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		s := NewScope()
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+				s.SetTag("a", "1").SetExtra("b", 2).SetLevel(Warning)
+				s.Breadcrumbs().NewDefault(nil)
+			}()
+
+			go func() {
+				defer wg.Done()
+				NewPacket().SetOptions(s.Clone(), s)
+			}()
+		}
+
+		wg.Wait()
+	})
+}
@@ -1,5 +1,12 @@
 package sentry
 
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
 // A SendQueue is used by the Sentry client to coordinate the transmission
 // of events. Custom queues can be used to control parallelism and circuit
 // breaking as necessary.
@@ -12,10 +19,28 @@ type SendQueue interface {
 	Enqueue(conf Config, packet Packet) QueuedEvent
 
 	// Shutdown is called by a client that wishes to stop the flow of
-	// events through a SendQueue.
+	// events through a SendQueue. Once it has been called, any new events
+	// will fail with ErrSendQueueShutdown. If wait is true, events which
+	// have already been enqueued will be sent before Shutdown returns,
+	// otherwise any which have not yet been sent will fail with
+	// ErrSendQueueShutdown. It is safe to call Shutdown more than once.
 	Shutdown(wait bool)
 }
 
+// A FlushableSendQueue is a SendQueue which is able to wait for the
+// events it has been given to be sent without being shut down. All of
+// the queues in this package implement it, which you can check with a
+// type assertion.
+type FlushableSendQueue interface {
+	SendQueue
+
+	// Flush waits until every event which was enqueued before it was
+	// called has completed, or until the context expires, in which case
+	// the context's error is returned. Unlike Shutdown, the queue will
+	// continue to accept new events.
+	Flush(ctx context.Context) error
+}
+
 const (
 	// ErrSendQueueFull is used when an attempt to enqueue a
 	// new event fails as a result of no buffer space being available.
@@ -25,6 +50,11 @@ const (
 	// a new event fails as a result of the queue having been shutdown
 	// already.
 	ErrSendQueueShutdown = ErrType("sentry: send queue was shutdown")
+
+	// ErrSendQueueNotFlushable is used when an attempt to flush a
+	// send queue fails as a result of it not implementing the
+	// FlushableSendQueue interface.
+	ErrSendQueueNotFlushable = ErrType("sentry: send queue cannot be flushed")
 )
 
 func init() {
@@ -41,6 +71,201 @@ func UseSendQueue(queue SendQueue) Option {
 	return &sendQueueOption{queue}
 }
 
+// sendEvent uses the transport configured for an event to send it to
+// Sentry, recording the number of attempts which were made and completing
+// the event with the result.
+func sendEvent(e QueuedEventInternal) {
+	cfg := e.Config()
+	t := cfg.Transport()
+	if t == nil {
+		e.Complete(errors.New("no transport configured"))
+		return
+	}
+
+	var attempts int
+	var err error
+	if rt, ok := t.(attemptCountingTransport); ok {
+		attempts, err = rt.sendCountingAttempts(cfg.DSN(), e.Packet())
+	} else {
+		attempts, err = 1, t.Send(cfg.DSN(), e.Packet())
+		if isRateLimitWindowError(err) {
+			// The transport didn't try to send the event because we're
+			// still waiting for a rate limit to expire.
+			attempts = 0
+		}
+	}
+
+	if ae, ok := e.(attemptRecordingEvent); ok {
+		ae.SetAttempts(attempts)
+	}
+
+	e.Complete(err)
+}
+
+// pendingEvents keeps track of the events which a SendQueue has accepted
+// but not yet completed, allowing them to be flushed. Its zero value is
+// ready to use and it is safe for concurrent use.
+type pendingEvents struct {
+	events map[QueuedEvent]chan struct{}
+	mutex  sync.Mutex
+}
+
+// Add registers an event which has been accepted by the queue.
+func (p *pendingEvents) Add(e QueuedEvent) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.events == nil {
+		p.events = map[QueuedEvent]chan struct{}{}
+	}
+
+	p.events[e] = make(chan struct{})
+}
+
+// Done marks an event as having been completed by the queue.
+func (p *pendingEvents) Done(e QueuedEvent) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if ch, ok := p.events[e]; ok {
+		close(ch)
+		delete(p.events, e)
+	}
+}
+
+// Wait blocks until all of the events which are currently pending have
+// been completed, or the context expires.
+func (p *pendingEvents) Wait(ctx context.Context) error {
+	p.mutex.Lock()
+	waits := make([]chan struct{}, 0, len(p.events))
+	for _, ch := range p.events {
+		waits = append(waits, ch)
+	}
+	p.mutex.Unlock()
+
+	for _, ch := range waits {
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// workerSendQueue is the core of the sequential and parallel send queues.
+// It buffers events and sends them using a fixed number of workers.
+type workerSendQueue struct {
+	name    string
+	buffer  chan QueuedEventInternal
+	pending pendingEvents
+
+	// mutex guards the queue's lifecycle, once shutdown is set the buffer
+	// is closed and the workers will exit after it has been emptied.
+	mutex    sync.RWMutex
+	shutdown bool
+	aborted  bool
+
+	wait sync.WaitGroup
+}
+
+// newWorkerSendQueue creates a workerSendQueue and starts its workers. The
+// name is used to identify the queue in the errors it returns.
+func newWorkerSendQueue(name string, workers, buffer int) *workerSendQueue {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if buffer < 0 {
+		buffer = 0
+	}
+
+	q := &workerSendQueue{
+		name:   name,
+		buffer: make(chan QueuedEventInternal, buffer),
+	}
+
+	q.wait.Add(workers)
+	for i := 0; i < workers; i++ {
+		go q.worker()
+	}
+
+	return q
+}
+
+func (q *workerSendQueue) Enqueue(cfg Config, packet Packet) QueuedEvent {
+	e := NewQueuedEvent(cfg, packet)
+	ei := e.(QueuedEventInternal)
+
+	// Holding the read lock prevents the buffer from being closed
+	// while we are writing to it.
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.shutdown {
+		err := errors.Errorf("%s: shutdown", q.name)
+		ei.Complete(errors.Wrap(err, ErrSendQueueShutdown.Error()))
+		return e
+	}
+
+	q.pending.Add(e)
+
+	select {
+	case q.buffer <- ei:
+	default:
+		q.pending.Done(e)
+
+		err := errors.Errorf("%s: buffer full", q.name)
+		ei.Complete(errors.Wrap(err, ErrSendQueueFull.Error()))
+	}
+
+	return e
+}
+
+func (q *workerSendQueue) Shutdown(wait bool) {
+	q.mutex.Lock()
+	if !wait {
+		q.aborted = true
+	}
+
+	if !q.shutdown {
+		q.shutdown = true
+		close(q.buffer)
+	}
+	q.mutex.Unlock()
+
+	if wait {
+		q.wait.Wait()
+	}
+}
+
+func (q *workerSendQueue) Flush(ctx context.Context) error {
+	return q.pending.Wait(ctx)
+}
+
+func (q *workerSendQueue) isAborted() bool {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return q.aborted
+}
+
+func (q *workerSendQueue) worker() {
+	defer q.wait.Done()
+
+	for e := range q.buffer {
+		if q.isAborted() {
+			err := errors.Errorf("%s: shutdown", q.name)
+			e.Complete(errors.Wrap(err, ErrSendQueueShutdown.Error()))
+		} else {
+			sendEvent(e)
+		}
+
+		q.pending.Done(e)
+	}
+}
+
 type sendQueueOption struct {
 	queue SendQueue
 }
@@ -1,9 +1,13 @@
 package sentry
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func ExampleUseSendQueue() {
@@ -33,3 +37,115 @@ func TestSendQueue(t *testing.T) {
 		assert.True(t, oo.Omit(), "it should always return true for calls to Omit()")
 	}
 }
+
+func TestSendEvent(t *testing.T) {
+	t.Run("with a transport", func(t *testing.T) {
+		tr := &testFlakyTransport{failures: 1, err: &httpStatusError{500}}
+		cl := NewClient(UseTransport(tr))
+
+		e := NewQueuedEvent(cl.(Config), NewPacket())
+		sendEvent(e.(QueuedEventInternal))
+
+		assert.EqualError(t, e.Error(), "got http status 500, expected 200", "it should complete the event with the transport's error")
+		assert.Equal(t, 1, e.(AttemptCountingEvent).Attempts(), "it should record a single attempt")
+	})
+
+	t.Run("with a retrying transport", func(t *testing.T) {
+		tr := &testFlakyTransport{failures: 1, err: &httpStatusError{500}}
+		cl := NewClient(UseTransport(NewRetryTransport(tr).WithBackoff(0, 0)))
+
+		e := NewQueuedEvent(cl.(Config), NewPacket())
+		sendEvent(e.(QueuedEventInternal))
+
+		assert.Nil(t, e.Error(), "it should complete the event successfully")
+		assert.Equal(t, 2, e.(AttemptCountingEvent).Attempts(), "it should record the number of attempts made by the transport")
+	})
+
+	t.Run("with a rate limited transport", func(t *testing.T) {
+		tr := &testFlakyTransport{failures: 1, err: errors.Wrap(&rateLimitWindowError{time.Now().Add(time.Minute)}, ErrRateLimited.Error())}
+		cl := NewClient(UseTransport(tr))
+
+		e := NewQueuedEvent(cl.(Config), NewPacket())
+		sendEvent(e.(QueuedEventInternal))
+
+		assert.True(t, ErrRateLimited.IsInstance(e.Error()), "it should complete the event with the transport's error")
+		assert.Equal(t, 0, e.(AttemptCountingEvent).Attempts(), "it should not record an attempt if the transport was backing off")
+	})
+
+	t.Run("without a transport", func(t *testing.T) {
+		cfg := &testConfig{}
+		require.Nil(t, cfg.Transport(), "the config should not have a transport")
+
+		e := NewQueuedEvent(cfg, NewPacket())
+		sendEvent(e.(QueuedEventInternal))
+
+		assert.EqualError(t, e.Error(), "no transport configured", "it should complete the event with an error")
+		assert.Equal(t, 0, e.(AttemptCountingEvent).Attempts(), "it should not record any attempts")
+	})
+}
+
+func TestPendingEvents(t *testing.T) {
+	cfg := NewClient().(Config)
+
+	t.Run("Wait()", func(t *testing.T) {
+		p := pendingEvents{}
+		assert.Nil(t, p.Wait(context.Background()), "it should not block if there are no pending events")
+
+		e1 := NewQueuedEvent(cfg, NewPacket())
+		e2 := NewQueuedEvent(cfg, NewPacket())
+		p.Add(e1)
+		p.Add(e2)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, p.Wait(ctx), "it should return the context's error if it expires")
+
+		p.Done(e1)
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, p.Wait(ctx), "it should wait for all of the pending events")
+
+		ch := make(chan error)
+		go func() {
+			ch <- p.Wait(context.Background())
+		}()
+
+		p.Done(e2)
+
+		select {
+		case err := <-ch:
+			assert.Nil(t, err, "it should not return an error once the events have completed")
+		case <-time.After(100 * time.Millisecond):
+			t.Error("timed out after 100ms waiting for the pending events")
+		}
+	})
+
+	t.Run("Done()", func(t *testing.T) {
+		p := pendingEvents{}
+		e := NewQueuedEvent(cfg, NewPacket())
+
+		p.Done(e)
+		p.Add(e)
+		p.Done(e)
+		p.Done(e)
+		assert.Empty(t, p.events, "it should remove completed events")
+	})
+}
+
+type testConfig struct {
+	dsn       string
+	transport Transport
+	queue     SendQueue
+}
+
+func (c *testConfig) DSN() string {
+	return c.dsn
+}
+
+func (c *testConfig) Transport() Transport {
+	return c.transport
+}
+
+func (c *testConfig) SendQueue() SendQueue {
+	return c.queue
+}
@@ -1,6 +1,8 @@
 package sentry
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -112,11 +114,165 @@ func TestSequentialSendQueue(t *testing.T) {
 		})
 	})
 
+	t.Run("Flush()", func(t *testing.T) {
+		q := NewSequentialSendQueue(10)
+		defer q.Shutdown(true)
+
+		assert.Implements(t, (*FlushableSendQueue)(nil), q, "it should implement the FlushableSendQueue interface")
+
+		transport := testNewTestTransport()
+		cl := NewClient(UseTransport(transport), UseSendQueue(q))
+
+		assert.Nil(t, q.(FlushableSendQueue).Flush(context.Background()), "it should not block if there are no events")
+
+		e1 := cl.Capture(Message("event 1"))
+		e2 := cl.Capture(Message("event 2"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, q.(FlushableSendQueue).Flush(ctx), "it should time out if the events cannot be sent")
+
+		ch := make(chan error)
+		go func() {
+			ch <- q.(FlushableSendQueue).Flush(context.Background())
+		}()
+
+		<-transport.ch
+		<-transport.ch
+
+		select {
+		case err := <-ch:
+			assert.Nil(t, err, "it should not return an error")
+			assert.Nil(t, e1.Error(), "the first event should have been sent")
+			assert.Nil(t, e2.Error(), "the second event should have been sent")
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("timed out waiting for the queue to be flushed")
+		}
+
+		e3 := cl.Capture(Message("event 3"))
+		<-transport.ch
+		assert.Nil(t, e3.Error(), "the queue should continue to send events after being flushed")
+	})
+
 	t.Run("Shutdown()", func(t *testing.T) {
 		q := NewSequentialSendQueue(10)
 
 		// It should be safe to call this repeatedly
 		q.Shutdown(true)
 		q.Shutdown(true)
+
+		waitForStart := func(t *testing.T, tr *testBlockingTransport) {
+			select {
+			case <-tr.started:
+			case <-time.After(100 * time.Millisecond):
+				t.Fatal("timed out waiting for the event to start sending")
+			}
+		}
+
+		t.Run("Drain", func(t *testing.T) {
+			q := NewSequentialSendQueue(10)
+
+			tr := testNewBlockingTransport()
+			cfg := NewClient(UseTransport(tr)).(Config)
+
+			events := []QueuedEvent{}
+			for i := 0; i < 3; i++ {
+				events = append(events, q.Enqueue(cfg, NewPacket()))
+			}
+
+			waitForStart(t, tr)
+
+			done := make(chan struct{})
+			go func() {
+				q.Shutdown(true)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				t.Fatal("shutdown should wait for the buffered events to be sent")
+			case <-time.After(10 * time.Millisecond):
+			}
+
+			tr.Release()
+
+			select {
+			case <-done:
+			case <-time.After(100 * time.Millisecond):
+				t.Fatal("timed out waiting for the queue to shutdown")
+			}
+
+			for _, e := range events {
+				assert.Nil(t, e.Error(), "buffered events should be sent when draining the queue")
+			}
+		})
+
+		t.Run("Abort", func(t *testing.T) {
+			q := NewSequentialSendQueue(10)
+
+			tr := testNewBlockingTransport()
+			cfg := NewClient(UseTransport(tr)).(Config)
+
+			e1 := q.Enqueue(cfg, NewPacket())
+			waitForStart(t, tr)
+
+			e2 := q.Enqueue(cfg, NewPacket())
+			e3 := q.Enqueue(cfg, NewPacket())
+
+			q.Shutdown(false)
+
+			e4 := q.Enqueue(cfg, NewPacket())
+			assert.True(t, ErrSendQueueShutdown.IsInstance(e4.Error()), "new events should fail with ErrSendQueueShutdown")
+
+			tr.Release()
+
+			assert.Nil(t, e1.Error(), "the event which was being sent should complete normally")
+			assert.True(t, ErrSendQueueShutdown.IsInstance(e2.Error()), "buffered events should fail with ErrSendQueueShutdown")
+			assert.True(t, ErrSendQueueShutdown.IsInstance(e3.Error()), "buffered events should fail with ErrSendQueueShutdown")
+
+			q.Shutdown(true)
+		})
+
+		t.Run("Concurrent", func(t *testing.T) {
+			for _, wait := range []bool{true, false} {
+				q := NewSequentialSendQueue(100)
+				cfg := NewClient(UseTransport(&testFlakyTransport{})).(Config)
+
+				events := make(chan QueuedEvent, 100)
+				wg := sync.WaitGroup{}
+				for i := 0; i < 10; i++ {
+					wg.Add(2)
+
+					go func() {
+						defer wg.Done()
+						for j := 0; j < 10; j++ {
+							events <- q.Enqueue(cfg, NewPacket())
+						}
+					}()
+
+					go func() {
+						defer wg.Done()
+						q.Shutdown(wait)
+					}()
+				}
+
+				wg.Wait()
+				close(events)
+
+				for e := range events {
+					select {
+					case <-e.WaitChannel():
+					case <-time.After(100 * time.Millisecond):
+						t.Fatal("timed out waiting for event completion")
+					}
+
+					if err := e.Error(); err != nil {
+						assert.True(t, ErrSendQueueShutdown.IsInstance(err), "events should either be sent or fail with ErrSendQueueShutdown")
+					}
+				}
+
+				q.Shutdown(true)
+			}
+		})
 	})
 }
@@ -0,0 +1,123 @@
+package sentry
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxSourceFileSize is the size of the largest source file which will
+	// be loaded to provide context for stack frames.
+	maxSourceFileSize = 1024 * 1024
+
+	// maxSourceCacheSize is the total size of the source files which will
+	// be kept in memory, after which the oldest will be discarded.
+	maxSourceCacheSize = 10 * 1024 * 1024
+)
+
+var defaultSourceCache = newSourceFileCache(maxSourceFileSize, maxSourceCacheSize)
+
+// sourceFileCache keeps the lines of recently used source files in memory
+// so that they do not need to be read from disk for every event.
+type sourceFileCache struct {
+	maxFileSize int64
+	maxSize     int64
+
+	size  int64
+	files map[string]*sourceFile
+	order []string
+	mutex sync.Mutex
+}
+
+type sourceFile struct {
+	lines []string
+	size  int64
+}
+
+func newSourceFileCache(maxFileSize, maxSize int64) *sourceFileCache {
+	return &sourceFileCache{
+		maxFileSize: maxFileSize,
+		maxSize:     maxSize,
+		files:       map[string]*sourceFile{},
+	}
+}
+
+// Lines returns the lines of a source file, or nil if it cannot be read or
+// is too large. Files which cannot be read are remembered so that we don't
+// repeatedly try to load them.
+func (c *sourceFileCache) Lines(path string) []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if f, ok := c.files[path]; ok {
+		return f.lines
+	}
+
+	f := c.load(path)
+	c.files[path] = f
+	c.order = append(c.order, path)
+	c.size += f.size
+
+	for c.size > c.maxSize && len(c.order) > 0 {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		c.size -= c.files[oldest].size
+		delete(c.files, oldest)
+	}
+
+	return f.lines
+}
+
+func (c *sourceFileCache) load(path string) *sourceFile {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > c.maxFileSize {
+		return &sourceFile{}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return &sourceFile{}
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	return &sourceFile{
+		lines: lines,
+		size:  int64(len(data)),
+	}
+}
+
+// addSourceContext populates the frame's context with up to the given
+// number of lines from either side of the line it refers to, if its source
+// file is available.
+func (f *stackTraceFrame) addSourceContext(lines int, cache *sourceFileCache) {
+	if lines < 0 || f.Line <= 0 || f.AbsoluteFilename == "" {
+		return
+	}
+
+	source := cache.Lines(f.AbsoluteFilename)
+	if f.Line > len(source) {
+		return
+	}
+
+	line := f.Line - 1
+
+	start := line - lines
+	if start < 0 {
+		start = 0
+	}
+
+	end := line + lines + 1
+	if end > len(source) {
+		end = len(source)
+	}
+
+	f.PreContext = append([]string{}, source[start:line]...)
+	f.Context = source[line]
+	f.PostContext = append([]string{}, source[line+1:end]...)
+}
@@ -0,0 +1,104 @@
+package sentry
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceFileCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sentry-go-source-")
+	require.Nil(t, err, "we should be able to create a temporary directory")
+	defer os.RemoveAll(dir)
+
+	writeFile := func(t *testing.T, name, content string) string {
+		path := filepath.Join(dir, name)
+		require.Nil(t, ioutil.WriteFile(path, []byte(content), 0600), "we should be able to write the source file")
+		return path
+	}
+
+	t.Run("Lines()", func(t *testing.T) {
+		c := newSourceFileCache(1024, 1024)
+		path := writeFile(t, "lines.go", "package main\r\n\r\nfunc main() {}\n")
+
+		assert.Equal(t, []string{"package main", "", "func main() {}", ""}, c.Lines(path), "it should split the file into lines")
+		assert.Equal(t, int64(31), c.size, "it should keep track of the cached size")
+
+		require.Nil(t, os.Remove(path), "we should be able to remove the source file")
+		assert.Equal(t, []string{"package main", "", "func main() {}", ""}, c.Lines(path), "it should cache the file's lines")
+	})
+
+	t.Run("Missing Files", func(t *testing.T) {
+		c := newSourceFileCache(1024, 1024)
+		path := filepath.Join(dir, "missing.go")
+
+		assert.Nil(t, c.Lines(path), "it should return nil if the file cannot be read")
+		assert.Contains(t, c.files, path, "it should remember that the file cannot be read")
+
+		assert.Nil(t, c.Lines(dir), "it should return nil for directories")
+	})
+
+	t.Run("Large Files", func(t *testing.T) {
+		c := newSourceFileCache(10, 1024)
+		path := writeFile(t, "large.go", strings.Repeat("a", 11))
+
+		assert.Nil(t, c.Lines(path), "it should not load files which are too large")
+		assert.Equal(t, int64(0), c.size, "it should not count files which were not loaded")
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		c := newSourceFileCache(1024, 10)
+		first := writeFile(t, "first.go", "123456")
+		second := writeFile(t, "second.go", "123456")
+
+		assert.NotNil(t, c.Lines(first), "it should load the first file")
+		assert.NotNil(t, c.Lines(second), "it should load the second file")
+
+		assert.NotContains(t, c.files, first, "it should evict the oldest file once the cache is full")
+		assert.Contains(t, c.files, second, "it should keep the newest file")
+		assert.Equal(t, int64(6), c.size, "it should update the cached size")
+	})
+}
+
+func TestStackTraceFrameAddSourceContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sentry-go-source-")
+	require.Nil(t, err, "we should be able to create a temporary directory")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "source.go")
+	require.Nil(t, ioutil.WriteFile(path, []byte("one\ntwo\nthree\nfour\nfive"), 0600), "we should be able to write the source file")
+
+	c := newSourceFileCache(1024, 1024)
+
+	f := &stackTraceFrame{AbsoluteFilename: path, Line: 3}
+	f.addSourceContext(1, c)
+	assert.Equal(t, []string{"two"}, f.PreContext, "it should include the preceding lines")
+	assert.Equal(t, "three", f.Context, "it should include the current line")
+	assert.Equal(t, []string{"four"}, f.PostContext, "it should include the following lines")
+
+	f = &stackTraceFrame{AbsoluteFilename: path, Line: 1}
+	f.addSourceContext(2, c)
+	assert.Equal(t, []string{}, f.PreContext, "it should handle frames at the start of the file")
+	assert.Equal(t, "one", f.Context, "it should include the current line")
+	assert.Equal(t, []string{"two", "three"}, f.PostContext, "it should include the following lines")
+
+	f = &stackTraceFrame{AbsoluteFilename: path, Line: 5}
+	f.addSourceContext(2, c)
+	assert.Equal(t, []string{"three", "four"}, f.PreContext, "it should include the preceding lines")
+	assert.Equal(t, "five", f.Context, "it should include the current line")
+	assert.Equal(t, []string{}, f.PostContext, "it should handle frames at the end of the file")
+
+	f = &stackTraceFrame{AbsoluteFilename: path, Line: 6}
+	f.addSourceContext(2, c)
+	assert.Empty(t, f.Context, "it should not add context for lines which are not in the file")
+
+	f = &stackTraceFrame{AbsoluteFilename: filepath.Join(dir, "missing.go"), Line: 1}
+	f.addSourceContext(2, c)
+	assert.Empty(t, f.Context, "it should not add context if the file cannot be read")
+	assert.Nil(t, f.PreContext, "it should not add context if the file cannot be read")
+}
@@ -2,28 +2,73 @@ package sentry
 
 var defaultInternalPrefixes = []string{"main"}
 
+var defaultSourceContextLines = 0
+
+var defaultMaxStackDepth = 100
+
 // AddInternalPrefixes allows you to easily add packages which will be considered
 // "internal" in your stack traces.
 func AddInternalPrefixes(prefixes ...string) {
 	defaultInternalPrefixes = append(defaultInternalPrefixes, prefixes...)
 }
 
+// SetSourceContextLines configures the number of lines of source code,
+// either side of the current line, which will be included in each frame of
+// your stack traces by default. Source files are read from the paths they
+// were compiled from, so this is only useful if your application's sources
+// are available where it is running. Setting it to zero, the default, will
+// disable the loading of source context.
+func SetSourceContextLines(lines int) {
+	defaultSourceContextLines = lines
+}
+
+// SetMaxStackDepth configures the maximum number of frames which will be
+// included in your stack traces. If a stack is deeper than this, the frames
+// furthest from where the stack trace was collected will be omitted and
+// Sentry will be told how many were removed. Setting it to zero will
+// include every frame.
+func SetMaxStackDepth(depth int) {
+	defaultMaxStackDepth = depth
+}
+
 // StackTraceOption wraps a stacktrace and gives you tools for selecting
 // where it is sourced from or what is classified as an internal module.
 type StackTraceOption interface {
 	Option
 	ForError(err error) StackTraceOption
 	WithInternalPrefixes(prefixes ...string) StackTraceOption
+
+	// WithSourceContext sets the number of lines of source code, either
+	// side of the current line, which will be included in each frame.
+	// Frames whose source files cannot be read will not include any.
+	WithSourceContext(lines int) StackTraceOption
 }
 
 // StackTrace allows you to add a StackTrace to the event you submit to Sentry,
 // allowing you to quickly determine where in your code the event was generated.
 func StackTrace() StackTraceOption {
+	frames, omitted := getStacktraceFrames(0)
+
+	return &stackTraceOption{
+		Frames:  frames,
+		Omitted: omitted,
+
+		internalPrefixes:   defaultInternalPrefixes,
+		sourceContextLines: defaultSourceContextLines,
+	}
+}
+
+// stackTraceForError creates a stack trace describing where an error was
+// created, which will be empty if the error does not record its stack.
+func stackTraceForError(err error) *stackTraceOption {
+	frames, omitted := getStacktraceFramesForError(err)
+
 	return &stackTraceOption{
-		Frames:  getStacktraceFrames(0),
-		Omitted: []int{},
+		Frames:  frames,
+		Omitted: omitted,
 
-		internalPrefixes: defaultInternalPrefixes,
+		internalPrefixes:   defaultInternalPrefixes,
+		sourceContextLines: defaultSourceContextLines,
 	}
 }
 
@@ -31,7 +76,8 @@ type stackTraceOption struct {
 	Frames  stackTraceFrames `json:"frames"`
 	Omitted []int            `json:"frames_omitted,omitempty"`
 
-	internalPrefixes []string
+	internalPrefixes   []string
+	sourceContextLines int
 }
 
 func (o *stackTraceOption) Class() string {
@@ -39,9 +85,10 @@ func (o *stackTraceOption) Class() string {
 }
 
 func (o *stackTraceOption) ForError(err error) StackTraceOption {
-	newFrames := getStacktraceFramesForError(err)
+	newFrames, omitted := getStacktraceFramesForError(err)
 	if newFrames.Len() > 0 {
 		o.Frames = newFrames
+		o.Omitted = omitted
 	}
 
 	return o
@@ -52,10 +99,38 @@ func (o *stackTraceOption) WithInternalPrefixes(prefixes ...string) StackTraceOp
 	return o
 }
 
+func (o *stackTraceOption) WithSourceContext(lines int) StackTraceOption {
+	o.sourceContextLines = lines
+	return o
+}
+
 func (o *stackTraceOption) Finalize() {
 	for _, frame := range o.Frames {
 		frame.ClassifyInternal(o.internalPrefixes)
+
+		if o.sourceContextLines > 0 {
+			frame.addSourceContext(o.sourceContextLines, defaultSourceCache)
+		}
+	}
+}
+
+// clone creates a copy of this stacktrace and its frames which will not be
+// affected by any later changes to it.
+func (o *stackTraceOption) clone() *stackTraceOption {
+	c := *o
+	c.Omitted = append([]int(nil), o.Omitted...)
+	c.internalPrefixes = append([]string(nil), o.internalPrefixes...)
+
+	if o.Frames != nil {
+		c.Frames = make(stackTraceFrames, len(o.Frames))
+		for i, frame := range o.Frames {
+			if frame != nil {
+				c.Frames[i] = frame.clone()
+			}
+		}
 	}
+
+	return &c
 }
 
 // stackTraceFrame describes the StackTrace for a given
@@ -81,3 +156,20 @@ type stackTraceFrame struct {
 	SymbolAddress     string                 `json:"symbol_addr,omitempty"`
 	InstructionOffset int                    `json:"instruction_offset,omitempty"`
 }
+
+// clone creates a copy of this frame which will not be affected by any
+// later changes to it.
+func (f *stackTraceFrame) clone() *stackTraceFrame {
+	c := *f
+	c.PreContext = append([]string(nil), f.PreContext...)
+	c.PostContext = append([]string(nil), f.PostContext...)
+
+	if f.Variables != nil {
+		c.Variables = make(map[string]interface{}, len(f.Variables))
+		for k, v := range f.Variables {
+			c.Variables[k] = v
+		}
+	}
+
+	return &c
+}
@@ -22,48 +22,98 @@ func (c stackTraceFrames) Reverse() {
 	}
 }
 
-func getStacktraceFramesForError(err error) stackTraceFrames {
+func getStacktraceFramesForError(err error) (stackTraceFrames, []int) {
 	if err, ok := err.(stackTracer); ok {
-		frames := stackTraceFrames{}
-		for _, f := range err.StackTrace() {
-			pc := uintptr(f) - 1
-			frame := getStacktraceFrame(pc)
-			if frame != nil {
-				frames = append(frames, frame)
-			}
+		// The frames recorded by pkg/errors hold return addresses, just
+		// like those returned by runtime.Callers.
+		st := err.StackTrace()
+		pcs := make([]uintptr, len(st))
+		for i, f := range st {
+			pcs[i] = uintptr(f)
 		}
 
-		frames.Reverse()
-		return frames
+		return limitStacktraceFrames(getStacktraceFramesForPCs(pcs))
+	}
+
+	return stackTraceFrames{}, []int{}
+}
+
+func getStacktraceFrames(skip int) (stackTraceFrames, []int) {
+	return limitStacktraceFrames(getStacktraceFramesForPCs(getCallers(skip + 1)))
+}
+
+// getPanicStacktraceFrames retrieves the stack of a goroutine which is
+// currently panicking, starting from the function which called panic().
+// If the goroutine is not panicking then the stack of the caller is used.
+func getPanicStacktraceFrames(skip int) (stackTraceFrames, []int) {
+	frames := getStacktraceFramesForPCs(getCallers(skip + 1))
+
+	for i := len(frames) - 1; i >= 0; i-- {
+		if frames[i].Package == "runtime" && frames[i].Function == "gopanic" {
+			frames = frames[:i]
+			break
+		}
 	}
 
-	return stackTraceFrames{}
+	return limitStacktraceFrames(frames)
 }
 
-func getStacktraceFrames(skip int) stackTraceFrames {
-	pcs := make([]uintptr, 30)
-	if c := runtime.Callers(skip+2, pcs); c > 0 {
-		frames := stackTraceFrames{}
-		for _, pc := range pcs {
-			frame := getStacktraceFrame(pc)
-			if frame != nil {
-				frames = append(frames, frame)
-			}
+// getCallers retrieves the program counters for every frame on the caller's
+// stack, growing its buffer until the whole stack fits in it.
+func getCallers(skip int) []uintptr {
+	pcs := make([]uintptr, 64)
+	for {
+		c := runtime.Callers(skip+2, pcs)
+		if c < len(pcs) {
+			return pcs[:c]
 		}
 
-		frames.Reverse()
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+}
+
+// getStacktraceFramesForPCs resolves a list of program counters, ordered from
+// the innermost call outwards, into frames ordered from the outermost call
+// inwards as Sentry expects. Calls to inlined functions are expanded into
+// their own frames.
+func getStacktraceFramesForPCs(pcs []uintptr) stackTraceFrames {
+	frames := stackTraceFrames{}
+	if len(pcs) == 0 {
 		return frames
 	}
 
-	return stackTraceFrames{}
+	callers := runtime.CallersFrames(pcs)
+	for {
+		f, more := callers.Next()
+		frames = append(frames, getStacktraceFrame(f))
+
+		if !more {
+			break
+		}
+	}
+
+	frames.Reverse()
+	return frames
+}
+
+// limitStacktraceFrames removes the outermost frames from a stack trace
+// if it is deeper than the maximum stack depth, returning the indices of
+// the frames which were removed so that they can be reported to Sentry.
+func limitStacktraceFrames(frames stackTraceFrames) (stackTraceFrames, []int) {
+	if defaultMaxStackDepth <= 0 || frames.Len() <= defaultMaxStackDepth {
+		return frames, []int{}
+	}
+
+	omitted := frames.Len() - defaultMaxStackDepth
+	return frames[omitted:], []int{0, omitted}
 }
 
-func getStacktraceFrame(pc uintptr) *stackTraceFrame {
+func getStacktraceFrame(f runtime.Frame) *stackTraceFrame {
 	frame := &stackTraceFrame{}
 
-	if fn := runtime.FuncForPC(pc); fn != nil {
-		frame.AbsoluteFilename, frame.Line = fn.FileLine(pc)
-		frame.Package, frame.Module, frame.Function = formatFuncName(fn.Name())
+	if f.Function != "" {
+		frame.AbsoluteFilename, frame.Line = f.File, f.Line
+		frame.Package, frame.Module, frame.Function = formatFuncName(f.Function)
 		frame.Filename = shortFilename(frame.AbsoluteFilename, frame.Package)
 	} else {
 		frame.AbsoluteFilename = "unknown"
@@ -14,51 +14,121 @@ func TestStackTraceGenerator(t *testing.T) {
 	t.Run("getStacktraceFramesForError()", func(t *testing.T) {
 		t.Run("StackTraceableError", func(t *testing.T) {
 			err := errors.New("test error")
-			frames := getStacktraceFramesForError(err)
+			frames, omitted := getStacktraceFramesForError(err)
 			if assert.NotEmpty(t, frames, "there should be frames from the error") {
 				assert.Equal(t, "TestStackTraceGenerator.func1.1", frames[frames.Len()-1].Function, "it should have the right function name as the top-most frame")
 			}
+			assert.Empty(t, omitted, "no frames should be omitted")
 		})
 
 		t.Run("error", func(t *testing.T) {
 			err := fmt.Errorf("test error")
-			frames := getStacktraceFramesForError(err)
+			frames, _ := getStacktraceFramesForError(err)
 			assert.Empty(t, frames, "there should be no frames from a normal error")
 		})
 	})
 
 	t.Run("getStacktraceFrames()", func(t *testing.T) {
 		t.Run("Skip", func(t *testing.T) {
-			frames := getStacktraceFrames(999999999)
+			frames, omitted := getStacktraceFrames(999999999)
 			assert.Empty(t, frames, "with an extreme skip, there should be no frames")
+			assert.Empty(t, omitted, "with an extreme skip, there should be no omitted frames")
 		})
 
 		t.Run("Current Function", func(t *testing.T) {
-			frames := getStacktraceFrames(0)
+			frames, _ := getStacktraceFrames(0)
 			if assert.NotEmpty(t, frames, "there should be frames from the current function") {
 				assert.Equal(t, "TestStackTraceGenerator.func2.2", frames[frames.Len()-1].Function, "it should have the right function name as the top-most frame")
 			}
 		})
+
+		t.Run("Inlined Functions", func(t *testing.T) {
+			frames, _ := testInlinableStacktrace()
+			require.Greater(t, frames.Len(), 2, "there should be frames from the current function")
+			assert.Equal(t, "testInlinableStacktrace", frames[frames.Len()-1].Function, "it should include a frame for the inlined function")
+			assert.Equal(t, "TestStackTraceGenerator.func2.3", frames[frames.Len()-2].Function, "it should include a frame for the function it was inlined into")
+		})
+
+		t.Run("Deep Stacks", func(t *testing.T) {
+			frames, omitted := testRecursiveStacktrace(defaultMaxStackDepth + 10)
+			assert.Equal(t, defaultMaxStackDepth, frames.Len(), "it should limit the number of frames")
+			if assert.Len(t, omitted, 2, "it should record the omitted frames") {
+				assert.Equal(t, 0, omitted[0], "the outermost frames should be omitted")
+				assert.Greater(t, omitted[1], 10, "it should record how many frames were omitted")
+			}
+			assert.Equal(t, "testRecursiveStacktrace", frames[frames.Len()-1].Function, "it should keep the innermost frames")
+		})
+
+		t.Run("SetMaxStackDepth()", func(t *testing.T) {
+			defer SetMaxStackDepth(defaultMaxStackDepth)
+
+			SetMaxStackDepth(2)
+			frames, omitted := getStacktraceFrames(0)
+			assert.Equal(t, 2, frames.Len(), "it should use the configured depth")
+			assert.NotEmpty(t, omitted, "it should record the omitted frames")
+
+			SetMaxStackDepth(0)
+			frames, omitted = testRecursiveStacktrace(200)
+			assert.Greater(t, frames.Len(), 200, "it should include every frame when the depth is unlimited")
+			assert.Empty(t, omitted, "it should not omit any frames when the depth is unlimited")
+		})
+	})
+
+	t.Run("getPanicStacktraceFrames()", func(t *testing.T) {
+		t.Run("Panicking", func(t *testing.T) {
+			var frames stackTraceFrames
+
+			func() {
+				defer func() {
+					recover()
+				}()
+
+				defer func() {
+					frames, _ = getPanicStacktraceFrames(0)
+				}()
+
+				testPanickingFunction("test panic")
+			}()
+
+			if assert.NotEmpty(t, frames, "there should be frames from the panicking goroutine") {
+				assert.Equal(t, "testPanickingFunction", frames[frames.Len()-1].Function, "the top-most frame should be the function which panicked")
+			}
+		})
+
+		t.Run("Not Panicking", func(t *testing.T) {
+			frames, _ := getPanicStacktraceFrames(0)
+			if assert.NotEmpty(t, frames, "there should be frames from the current function") {
+				assert.Equal(t, "TestStackTraceGenerator.func3.2", frames[frames.Len()-1].Function, "the top-most frame should be the caller")
+			}
+		})
 	})
 
 	t.Run("getStackTraceFrame()", func(t *testing.T) {
-		pc, file, line, ok := runtime.Caller(0)
+		pcs := make([]uintptr, 1)
+		_, file, line, ok := runtime.Caller(0)
+		require.Equal(t, 1, runtime.Callers(1, pcs), "we should be able to get the current caller")
 		require.True(t, ok, "we should be able to get the current caller")
 
-		frame := getStacktraceFrame(pc)
+		// runtime.Callers is called on the line after runtime.Caller
+		line++
+		f, _ := runtime.CallersFrames(pcs).Next()
+
+		frame := getStacktraceFrame(f)
 		require.NotNil(t, frame, "the frame should not be nil")
 
 		assert.Equal(t, file, frame.AbsoluteFilename, "the filename for the frame should match the caller")
 		assert.Equal(t, line, frame.Line, "the line from the frame should match the caller")
 
 		assert.Regexp(t, ".*/sentry-go/stacktraceGen_test.go$", frame.Filename, "it should have the correct filename")
-		assert.Equal(t, "TestStackTraceGenerator.func3", frame.Function, "it should have the correct function name")
+		assert.Equal(t, "TestStackTraceGenerator.func4", frame.Function, "it should have the correct function name")
 		assert.Equal(t, "sentry-go/v2", frame.Module, "it should have the correct module name")
 		assert.Equal(t, "github.com/SierraSoftworks/sentry-go/v2", frame.Package, "it should have the correct package name")
 	})
 
 	t.Run("stackTraceFrame.ClassifyInternal()", func(t *testing.T) {
-		frames := getStacktraceFrames(0)
+		// Only the frames which were actually called are included, so we
+		// collect them from within a few nested calls.
+		frames, _ := testRecursiveStacktrace(1)
 		require.Greater(t, frames.Len(), 3, "the number of frames should be more than 3")
 
 		for i, frame := range frames {
@@ -66,7 +136,7 @@ func TestStackTraceGenerator(t *testing.T) {
 			frame.ClassifyInternal([]string{"github.com/SierraSoftworks/sentry-go"})
 		}
 
-		assert.True(t, frames[frames.Len()-1].InApp, "the top-most frame should be marked as internal (this function)")
+		assert.True(t, frames[frames.Len()-1].InApp, "the top-most frame should be marked as internal (the recursive helper)")
 		assert.False(t, frames[0].InApp, "the bottom-most frame should be marked as external (the test harness main method)")
 	})
 
@@ -110,3 +180,16 @@ func TestStackTraceGenerator(t *testing.T) {
 		})
 	})
 }
+
+func testInlinableStacktrace() (stackTraceFrames, []int) {
+	return getStacktraceFrames(0)
+}
+
+//go:noinline
+func testRecursiveStacktrace(depth int) (stackTraceFrames, []int) {
+	if depth <= 0 {
+		return getStacktraceFrames(0)
+	}
+
+	return testRecursiveStacktrace(depth - 1)
+}
@@ -38,6 +38,17 @@ func TestAddInternalPrefixes(t *testing.T) {
 	assert.Contains(t, defaultInternalPrefixes, "github.com/SierraSoftworks/sentry-go")
 }
 
+func ExampleSetSourceContextLines() {
+	// Include 5 lines of source code either side of each stack frame,
+	// if your sources are available where your application is running.
+	SetSourceContextLines(5)
+}
+
+func ExampleSetMaxStackDepth() {
+	// Only include the 50 innermost frames in your stack traces
+	SetMaxStackDepth(50)
+}
+
 func TestStackTrace(t *testing.T) {
 	o := StackTrace()
 	require.NotNil(t, o, "it should return a non-nil option")
@@ -71,4 +82,29 @@ func TestStackTrace(t *testing.T) {
 			assert.True(t, sti.Frames[len(sti.Frames)-1].InApp, "the final frame should be marked as internal")
 		}
 	}
+
+	t.Run("WithSourceContext()", func(t *testing.T) {
+		o := StackTrace()
+		sti := o.(*stackTraceOption)
+		assert.Equal(t, defaultSourceContextLines, sti.sourceContextLines, "it should start out with the default number of source context lines")
+
+		assert.Same(t, o, o.WithSourceContext(2), "it should return the option for chaining")
+		assert.Equal(t, 2, sti.sourceContextLines, "it should set the number of source context lines")
+
+		sti.Finalize()
+
+		frame := sti.Frames[len(sti.Frames)-1]
+		assert.Contains(t, frame.Context, "getStacktraceFrames(0)", "it should include the current line of the final frame")
+		assert.Len(t, frame.PreContext, 2, "it should include the preceding lines")
+		assert.Len(t, frame.PostContext, 2, "it should include the following lines")
+	})
+
+	t.Run("Without Source Context", func(t *testing.T) {
+		sti := StackTrace().(*stackTraceOption)
+		sti.Finalize()
+
+		for _, frame := range sti.Frames {
+			assert.Empty(t, frame.Context, "it should not load source context by default")
+		}
+	})
 }
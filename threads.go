@@ -0,0 +1,272 @@
+package sentry
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// maxThreadsStackSize is the largest buffer which will be used to collect
+// the stacks of all goroutines.
+const maxThreadsStackSize = 16 * 1024 * 1024
+
+// ThreadsOption describes the stacks of every goroutine in your application
+// at the time it was created, which is useful when investigating deadlocks
+// and other fatal errors.
+type ThreadsOption interface {
+	Option
+
+	// WithCrashed marks the goroutine which created this option as the
+	// one which crashed, for example because it panicked.
+	WithCrashed() ThreadsOption
+
+	// WithInternalPrefixes adds prefixes which will be used to mark the
+	// frames of each goroutine as internal to your application.
+	WithInternalPrefixes(prefixes ...string) ThreadsOption
+}
+
+// Threads allows you to include the stacks of all of your application's
+// goroutines in the event you send to Sentry. The goroutine which created
+// the option will be marked as the current thread.
+func Threads() ThreadsOption {
+	return &threadsOption{
+		Threads: getThreads(1),
+	}
+}
+
+type threadsOption struct {
+	Threads []*threadInfo `json:"values"`
+}
+
+func (o *threadsOption) Class() string {
+	return "threads"
+}
+
+func (o *threadsOption) WithCrashed() ThreadsOption {
+	for _, thread := range o.Threads {
+		if thread.Current {
+			thread.Crashed = true
+		}
+	}
+
+	return o
+}
+
+func (o *threadsOption) WithInternalPrefixes(prefixes ...string) ThreadsOption {
+	for _, thread := range o.Threads {
+		if thread.StackTrace != nil {
+			thread.StackTrace.WithInternalPrefixes(prefixes...)
+		}
+	}
+
+	return o
+}
+
+func (o *threadsOption) Finalize() {
+	for _, thread := range o.Threads {
+		if thread.StackTrace != nil {
+			thread.StackTrace.Finalize()
+		}
+	}
+}
+
+// threadInfo describes a single goroutine using Sentry's threads interface.
+type threadInfo struct {
+	ID         uint64            `json:"id"`
+	Name       string            `json:"name,omitempty"`
+	State      string            `json:"state,omitempty"`
+	WaitReason string            `json:"wait_reason,omitempty"`
+	Crashed    bool              `json:"crashed"`
+	Current    bool              `json:"current"`
+	Main       bool              `json:"main"`
+	StackTrace *stackTraceOption `json:"stacktrace,omitempty"`
+}
+
+// goroutineStates are the states which the Go runtime reports for goroutines
+// which are not waiting on something. Any other state is a wait reason.
+var goroutineStates = map[string]struct{}{
+	"idle":      {},
+	"runnable":  {},
+	"running":   {},
+	"syscall":   {},
+	"dead":      {},
+	"copystack": {},
+	"preempted": {},
+}
+
+var goroutineHeaderPattern = regexp.MustCompile(`^goroutine (\d+)(?: [^\[]*)?\[([^\]]*)\]:$`)
+
+// getThreads collects the stacks of all goroutines, skipping the given
+// number of frames from the current goroutine's stack.
+func getThreads(skip int) []*threadInfo {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxThreadsStackSize {
+			buf = buf[:n]
+			break
+		}
+
+		buf = make([]byte, len(buf)*2)
+	}
+
+	threads := parseThreads(buf)
+	for _, thread := range threads {
+		if !thread.Current {
+			continue
+		}
+
+		// Remove this function, and the requested number of its callers,
+		// from the end of the current goroutine's stack.
+		frames := thread.StackTrace.Frames
+		if drop := skip + 1; drop < frames.Len() {
+			thread.StackTrace.Frames = frames[:frames.Len()-drop]
+		}
+	}
+
+	return threads
+}
+
+// parseThreads parses the output of runtime.Stack into a list of threads.
+// The first goroutine in the output is the one which called runtime.Stack
+// and is marked as the current thread.
+func parseThreads(stack []byte) []*threadInfo {
+	threads := []*threadInfo{}
+
+	var thread *threadInfo
+	var frame *stackTraceFrame
+
+	finishThread := func() {
+		if thread == nil {
+			return
+		}
+
+		thread.StackTrace.Frames.Reverse()
+		thread.StackTrace.Frames, thread.StackTrace.Omitted = limitStacktraceFrames(thread.StackTrace.Frames)
+		threads = append(threads, thread)
+		thread = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(stack))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(stack)+1)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			finishThread()
+
+		case strings.HasPrefix(line, "goroutine "):
+			finishThread()
+
+			m := goroutineHeaderPattern.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+
+			id, err := strconv.ParseUint(m[1], 10, 64)
+			if err != nil {
+				continue
+			}
+
+			thread = &threadInfo{
+				ID:      id,
+				Name:    "goroutine " + m[1],
+				Current: len(threads) == 0,
+				Main:    id == 1,
+				StackTrace: &stackTraceOption{
+					Frames:  stackTraceFrames{},
+					Omitted: []int{},
+
+					internalPrefixes:   append([]string{}, defaultInternalPrefixes...),
+					sourceContextLines: defaultSourceContextLines,
+				},
+			}
+
+			thread.State, thread.WaitReason = parseGoroutineStatus(m[2])
+
+		case thread == nil:
+			continue
+
+		case strings.HasPrefix(line, "\t"):
+			if frame != nil {
+				frame.AbsoluteFilename, frame.Line = parseGoroutineFileLine(line)
+				frame.Filename = shortFilename(frame.AbsoluteFilename, frame.Package)
+				frame = nil
+			}
+
+		case strings.HasPrefix(line, "..."):
+			// The runtime elides frames from very deep stacks.
+			continue
+
+		case strings.HasPrefix(line, "created by "):
+			// This describes where the goroutine was started from, rather
+			// than a frame on its stack, so we include it in its name.
+			thread.Name = fmt.Sprintf("%s (%s)", thread.Name, line)
+			frame = nil
+
+		default:
+			frame = &stackTraceFrame{}
+			if name := parseGoroutineFuncName(line); strings.Contains(name, ".") {
+				frame.Package, frame.Module, frame.Function = formatFuncName(name)
+			} else {
+				// Builtins, like panic, are not part of a package.
+				frame.Function = name
+			}
+
+			thread.StackTrace.Frames = append(thread.StackTrace.Frames, frame)
+		}
+	}
+
+	finishThread()
+	return threads
+}
+
+// parseGoroutineStatus splits the status of a goroutine, like
+// "chan receive, 2 minutes", into its state and wait reason.
+func parseGoroutineStatus(status string) (state, waitReason string) {
+	reason := strings.TrimSpace(strings.SplitN(status, ",", 2)[0])
+	if _, ok := goroutineStates[reason]; ok {
+		return reason, ""
+	}
+
+	return "waiting", reason
+}
+
+// parseGoroutineFuncName extracts the name of a function from a line like
+// "main.(*T).wait(0x1, 0x2)".
+func parseGoroutineFuncName(line string) string {
+	if strings.HasSuffix(line, ")") {
+		if idx := strings.LastIndex(line, "("); idx > 0 {
+			line = line[:idx]
+		}
+	}
+
+	return line
+}
+
+// parseGoroutineFileLine extracts the file and line number from a line like
+// "\t/src/main.go:11 +0x1d".
+func parseGoroutineFileLine(line string) (string, int) {
+	line = strings.TrimSpace(line)
+	if idx := strings.LastIndex(line, " +0x"); idx != -1 {
+		line = line[:idx]
+	}
+
+	idx := strings.LastIndex(line, ":")
+	if idx == -1 {
+		return line, 0
+	}
+
+	n, err := strconv.Atoi(line[idx+1:])
+	if err != nil {
+		return line, 0
+	}
+
+	return line[:idx], n
+}
@@ -0,0 +1,194 @@
+package sentry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleThreads() {
+	cl := NewClient()
+
+	cl.Capture(
+		Message("Deadlock detected"),
+		// Include the stacks of all of your goroutines, marking the
+		// current one as the one which crashed.
+		Threads().
+			WithCrashed().
+			WithInternalPrefixes("github.com/SierraSoftworks/sentry-go"),
+	)
+}
+
+const testGoroutineStacks = `goroutine 7 [running]:
+main.main()
+	/src/main.go:19 +0xc5
+
+goroutine 1 [chan receive, 2 minutes, locked to thread]:
+github.com/example/project.(*Worker).wait(...)
+	/go/src/github.com/example/project/worker.go:11
+created by main.main in goroutine 7
+	/src/main.go:15 +0x85
+
+goroutine 8 [select (no cases)]:
+panic({0x4a8f40?, 0x4e9a40?})
+	/usr/local/go/src/runtime/panic.go:770 +0x132
+main.main.func1()
+	/src/main.go:16 +0xf
+...additional frames elided...
+created by main.main
+	/src/main.go:16 +0x91
+
+goroutine 9 [runnable]:
+	goroutine running on other thread; stack unavailable
+`
+
+func TestThreads(t *testing.T) {
+	o := Threads()
+	require.NotNil(t, o, "it should not return nil")
+	assert.Implements(t, (*Option)(nil), o, "it should implement the Option interface")
+	assert.Implements(t, (*FinalizeableOption)(nil), o, "it should implement the FinalizeableOption interface")
+	assert.Equal(t, "threads", o.Class(), "it should use the right option class")
+
+	to, ok := o.(*threadsOption)
+	require.True(t, ok, "it should actually be a *threadsOption")
+
+	t.Run("Threads()", func(t *testing.T) {
+		block := make(chan struct{})
+		started := make(chan struct{})
+		defer close(block)
+
+		go func() {
+			close(started)
+			<-block
+		}()
+		<-started
+
+		threads := Threads().(*threadsOption).Threads
+		require.NotEmpty(t, threads, "it should include the goroutines")
+
+		current := threads[0]
+		assert.True(t, current.Current, "the first goroutine should be the current one")
+		assert.Equal(t, "running", current.State, "the current goroutine should be running")
+		if assert.NotEmpty(t, current.StackTrace.Frames, "the current goroutine should have frames") {
+			assert.Equal(t, "TestThreads.func1", current.StackTrace.Frames[current.StackTrace.Frames.Len()-1].Function, "the innermost frame should be the caller of Threads()")
+		}
+
+		found := false
+		for _, thread := range threads[1:] {
+			assert.False(t, thread.Current, "only one goroutine should be marked as current")
+
+			frames := thread.StackTrace.Frames
+			if frames.Len() > 0 && frames[frames.Len()-1].Function == "TestThreads.func1.1" {
+				found = true
+				assert.Equal(t, "waiting", thread.State, "the blocked goroutine should be waiting")
+				assert.Equal(t, "chan receive", thread.WaitReason, "the blocked goroutine should include its wait reason")
+			}
+		}
+		assert.True(t, found, "it should include the blocked goroutine")
+	})
+
+	t.Run("WithCrashed()", func(t *testing.T) {
+		assert.Same(t, o, o.WithCrashed(), "it should return the option for chaining")
+
+		for _, thread := range to.Threads {
+			assert.Equal(t, thread.Current, thread.Crashed, "only the current goroutine should be marked as crashed")
+		}
+	})
+
+	t.Run("WithInternalPrefixes()", func(t *testing.T) {
+		assert.Same(t, o, o.WithInternalPrefixes("github.com/SierraSoftworks/sentry-go"), "it should return the option for chaining")
+
+		for _, thread := range to.Threads {
+			assert.Contains(t, thread.StackTrace.internalPrefixes, "github.com/SierraSoftworks/sentry-go", "it should add the prefixes to every goroutine")
+		}
+	})
+
+	t.Run("Finalize()", func(t *testing.T) {
+		to.Finalize()
+
+		frames := to.Threads[0].StackTrace.Frames
+		require.NotEmpty(t, frames, "the current goroutine should have frames")
+		assert.True(t, frames[frames.Len()-1].InApp, "it should classify the frames of each goroutine")
+	})
+
+	t.Run("MarshalJSON()", func(t *testing.T) {
+		serialized, ok := testOptionsSerialize(t, o).(map[string]interface{})
+		require.True(t, ok, "it should serialize to an object")
+
+		values, ok := serialized["values"].([]interface{})
+		require.True(t, ok, "it should include a list of threads")
+		require.NotEmpty(t, values, "it should include the goroutines")
+
+		thread, ok := values[0].(map[string]interface{})
+		require.True(t, ok, "each thread should be an object")
+		assert.Equal(t, true, thread["current"], "it should include whether the thread is current")
+		assert.Equal(t, true, thread["crashed"], "it should include whether the thread crashed")
+		assert.Contains(t, thread, "stacktrace", "it should include the thread's stacktrace")
+	})
+}
+
+func TestParseThreads(t *testing.T) {
+	threads := parseThreads([]byte(testGoroutineStacks))
+	require.Len(t, threads, 4, "it should parse every goroutine")
+
+	t.Run("Running", func(t *testing.T) {
+		thread := threads[0]
+		assert.Equal(t, uint64(7), thread.ID, "it should parse the goroutine's ID")
+		assert.Equal(t, "goroutine 7", thread.Name, "it should name the goroutine")
+		assert.True(t, thread.Current, "the first goroutine should be marked as current")
+		assert.False(t, thread.Main, "it should not be marked as the main goroutine")
+		assert.Equal(t, "running", thread.State, "it should parse the goroutine's state")
+		assert.Empty(t, thread.WaitReason, "it should not have a wait reason")
+
+		require.Len(t, thread.StackTrace.Frames, 1, "it should parse the goroutine's frames")
+		frame := thread.StackTrace.Frames[0]
+		assert.Equal(t, "main", frame.Package, "it should parse the frame's package")
+		assert.Equal(t, "main", frame.Function, "it should parse the frame's function")
+		assert.Equal(t, "/src/main.go", frame.AbsoluteFilename, "it should parse the frame's file")
+		assert.Equal(t, 19, frame.Line, "it should parse the frame's line")
+	})
+
+	t.Run("Waiting", func(t *testing.T) {
+		thread := threads[1]
+		assert.Equal(t, uint64(1), thread.ID, "it should parse the goroutine's ID")
+		assert.False(t, thread.Current, "only the first goroutine should be marked as current")
+		assert.True(t, thread.Main, "goroutine 1 should be marked as the main goroutine")
+		assert.Equal(t, "waiting", thread.State, "it should mark the goroutine as waiting")
+		assert.Equal(t, "chan receive", thread.WaitReason, "it should parse the goroutine's wait reason")
+
+		assert.Equal(t, "goroutine 1 (created by main.main in goroutine 7)", thread.Name, "it should include the goroutine's creator in its name")
+
+		frames := thread.StackTrace.Frames
+		require.Len(t, frames, 1, "it should not include the creator as a frame")
+		assert.Equal(t, "github.com/example/project", frames[0].Package, "it should parse the frame's package")
+		assert.Equal(t, "(*Worker).wait", frames[0].Function, "it should parse method names")
+		assert.Equal(t, "github.com/example/project/worker.go", frames[0].Filename, "it should shorten the frame's filename")
+		assert.Equal(t, 11, frames[0].Line, "it should parse lines without an offset")
+	})
+
+	t.Run("Elided Frames", func(t *testing.T) {
+		thread := threads[2]
+		assert.Equal(t, "waiting", thread.State, "it should mark the goroutine as waiting")
+		assert.Equal(t, "select (no cases)", thread.WaitReason, "it should parse the goroutine's wait reason")
+
+		assert.Equal(t, "goroutine 8 (created by main.main)", thread.Name, "it should include the goroutine's creator in its name")
+
+		frames := thread.StackTrace.Frames
+		require.Len(t, frames, 2, "it should skip the elided frames marker")
+		assert.Equal(t, "main.func1", frames[0].Function, "it should parse closures")
+		assert.Equal(t, "panic", frames[1].Function, "it should parse builtin functions")
+		assert.Equal(t, "", frames[1].Package, "builtin functions should not have a package")
+		assert.Equal(t, 770, frames[1].Line, "builtin functions should include their line")
+	})
+
+	t.Run("Unavailable Stack", func(t *testing.T) {
+		thread := threads[3]
+		assert.Equal(t, "runnable", thread.State, "it should parse the goroutine's state")
+		assert.Empty(t, thread.StackTrace.Frames, "it should not have any frames")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		assert.Empty(t, parseThreads([]byte{}), "it should not return any threads")
+	})
+}
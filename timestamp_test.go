@@ -26,6 +26,9 @@ func TestTimestamp(t *testing.T) {
 	assert.Equal(t, "timestamp", o.Class(), "it should use the right option class")
 
 	t.Run("MarshalJSON()", func(t *testing.T) {
-		assert.Equal(t, now.UTC().Format("2006-01-02T15:04:05"), testOptionsSerialize(t, o), "it should serialize to a string")
+		assert.Equal(t, now.UTC().Format(time.RFC3339Nano), testOptionsSerialize(t, o), "it should serialize to a string")
+
+		ts := time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.FixedZone("UTC+2", 2*60*60))
+		assert.Equal(t, "2020-01-02T01:04:05.123456Z", testOptionsSerialize(t, Timestamp(ts)), "it should include fractional seconds in UTC")
 	})
 }